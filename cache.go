@@ -0,0 +1,407 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheEntry is one cached upstream body or parsed entity.
+type cacheEntry struct {
+	Key      string    `json:"key"`
+	StoredAt time.Time `json:"stored_at"`
+	Body     []byte    `json:"body"`
+}
+
+// cacheStore keeps entries by key. Freshness is decided by the reader, so a
+// store never drops entries just because a TTL passed.
+type cacheStore interface {
+	Get(key string) (cacheEntry, bool)
+	Set(entry cacheEntry)
+}
+
+// responseCache holds upstream pages and parsed finished-match reports.
+// main replaces it with the configured memory+disk store.
+var responseCache cacheStore = newMemoryStore(2000)
+
+// memoryStore is a bounded in-process store; when full the oldest entry is evicted.
+type memoryStore struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]cacheEntry
+}
+
+func newMemoryStore(max int) *memoryStore {
+	return &memoryStore{max: max, entries: map[string]cacheEntry{}}
+}
+
+func (m *memoryStore) Get(key string) (cacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	return e, ok
+}
+
+func (m *memoryStore) Set(entry cacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.entries[entry.Key]; !exists && m.max > 0 && len(m.entries) >= m.max {
+		oldestKey := ""
+		var oldest time.Time
+		for k, e := range m.entries {
+			if oldestKey == "" || e.StoredAt.Before(oldest) {
+				oldestKey, oldest = k, e.StoredAt
+			}
+		}
+		delete(m.entries, oldestKey)
+	}
+	m.entries[entry.Key] = entry
+}
+
+// diskStore keeps one JSON file per key so cached pages survive restarts.
+type diskStore struct {
+	dir string
+}
+
+func newDiskStore(dir string) (*diskStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &diskStore{dir: dir}, nil
+}
+
+func (d *diskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(d.dir, name[:2], name+".json")
+}
+
+func (d *diskStore) Get(key string) (cacheEntry, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return cacheEntry{}, false
+	}
+	return e, true
+}
+
+func (d *diskStore) Set(entry cacheEntry) {
+	p := d.path(entry.Key)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		log.Printf("cache: %v", err)
+		return
+	}
+	// Write via a temp file so concurrent readers never see half an entry
+	tmp := p + ".tmp" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		log.Printf("cache: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		os.Remove(tmp)
+		log.Printf("cache: %v", err)
+	}
+}
+
+// prune removes entries not written for longer than maxAge.
+func (d *diskStore) prune(maxAge time.Duration) {
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	filepath.WalkDir(d.dir, func(p string, de fs.DirEntry, err error) error {
+		if err != nil || de.IsDir() {
+			return nil
+		}
+		if info, err := de.Info(); err == nil && info.ModTime().Before(cutoff) {
+			if os.Remove(p) == nil {
+				removed++
+			}
+		}
+		return nil
+	})
+	if removed > 0 {
+		log.Printf("cache: pruned %d entries older than %s", removed, maxAge)
+	}
+}
+
+// tieredStore reads memory first and falls back to disk, promoting disk hits.
+type tieredStore struct {
+	mem  *memoryStore
+	disk *diskStore
+}
+
+func (t *tieredStore) Get(key string) (cacheEntry, bool) {
+	if e, ok := t.mem.Get(key); ok {
+		return e, true
+	}
+	e, ok := t.disk.Get(key)
+	if ok {
+		t.mem.Set(e)
+	}
+	return e, ok
+}
+
+func (t *tieredStore) Set(entry cacheEntry) {
+	t.mem.Set(entry)
+	t.disk.Set(entry)
+}
+
+// newCacheStoreFromEnv builds the memory+disk store. CACHE_DIR picks the
+// directory (default: the user cache dir); CACHE_DIR=off keeps memory only.
+func newCacheStoreFromEnv() cacheStore {
+	mem := newMemoryStore(envInt("CACHE_MEMORY_ENTRIES", 2000))
+	dir := os.Getenv("CACHE_DIR")
+	if dir == "off" {
+		return mem
+	}
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			log.Printf("cache: no user cache dir, using memory only: %v", err)
+			return mem
+		}
+		dir = filepath.Join(base, "facr-scraper")
+	}
+	disk, err := newDiskStore(dir)
+	if err != nil {
+		log.Printf("cache: disk store disabled: %v", err)
+		return mem
+	}
+	go disk.prune(envDuration("CACHE_MAX_AGE", 30*24*time.Hour))
+	return &tieredStore{mem: mem, disk: disk}
+}
+
+// cacheTTLs are the freshness limits per kind of upstream resource.
+type cacheTTLs struct {
+	Club           time.Duration // club pages and club search
+	Table          time.Duration // IS standings
+	Fixtures       time.Duration // IS competition detail and fotbal.cz competition pages
+	Report         time.Duration // IS match and delegation report pages
+	FinishedReport time.Duration // parsed reports of matches that are over
+	Logo           time.Duration // logos resolved by club search; placeholders are kept for a day at most
+	Other          time.Duration
+}
+
+// cacheTTLsFromEnv reads CACHE_TTL_CLUB, CACHE_TTL_TABLE, CACHE_TTL_FIXTURES,
+// CACHE_TTL_REPORT, CACHE_TTL_FINISHED_REPORT, CACHE_TTL_LOGO and
+// CACHE_TTL_OTHER. A zero
+// duration disables caching for that kind.
+func cacheTTLsFromEnv() cacheTTLs {
+	return cacheTTLs{
+		Club:           envDuration("CACHE_TTL_CLUB", 6*time.Hour),
+		Table:          envDuration("CACHE_TTL_TABLE", 30*time.Minute),
+		Fixtures:       envDuration("CACHE_TTL_FIXTURES", 15*time.Minute),
+		Report:         envDuration("CACHE_TTL_REPORT", 15*time.Minute),
+		FinishedReport: envDuration("CACHE_TTL_FINISHED_REPORT", 30*24*time.Hour),
+		Logo:           envDuration("CACHE_TTL_LOGO", 30*24*time.Hour),
+		Other:          envDuration("CACHE_TTL_OTHER", 10*time.Minute),
+	}
+}
+
+// activeTTLs is used by the caching fetcher and the parsed report cache.
+var activeTTLs = cacheTTLsFromEnv()
+
+// forURL picks the TTL for an upstream URL.
+func (t cacheTTLs) forURL(pageURL string) time.Duration {
+	switch {
+	case strings.Contains(pageURL, "zapis-o-utkani-report") || strings.Contains(pageURL, "zapas-delegace-report"):
+		return t.Report
+	case strings.Contains(pageURL, "tabulky-souteze"):
+		return t.Table
+	case strings.Contains(pageURL, "detail-souteze") || strings.Contains(pageURL, "/table/"):
+		return t.Fixtures
+	case strings.Contains(pageURL, "/club/club/") || strings.Contains(pageURL, "/club/hledej"):
+		return t.Club
+	}
+	return t.Other
+}
+
+// cachingFetcher serves upstream pages from the store while they are fresh
+// and falls back to a stale copy when upstream is failing.
+type cachingFetcher struct {
+	next  Fetcher
+	store cacheStore
+	ttls  cacheTTLs
+}
+
+func newCachingFetcher(next Fetcher, store cacheStore, ttls cacheTTLs) *cachingFetcher {
+	return &cachingFetcher{next: next, store: store, ttls: ttls}
+}
+
+func (c *cachingFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	ttl := c.ttls.forURL(pageURL)
+	if ttl <= 0 {
+		return c.next.Fetch(ctx, pageURL)
+	}
+	cached, haveCached := c.store.Get(pageURL)
+	if haveCached && !freshFetch(ctx) && time.Since(cached.StoredAt) < ttl {
+		recordCacheUse(ctx, true, cached.StoredAt)
+		return cached.Body, nil
+	}
+	body, err := c.next.Fetch(ctx, pageURL)
+	if err != nil {
+		if haveCached && retryable(err) && ctx.Err() == nil {
+			log.Printf("cache: serving stale %s after fetch error: %v", pageURL, err)
+			recordCacheUse(ctx, true, cached.StoredAt)
+			return cached.Body, nil
+		}
+		return nil, err
+	}
+	c.store.Set(cacheEntry{Key: pageURL, StoredAt: time.Now(), Body: body})
+	recordCacheUse(ctx, false, time.Time{})
+	return body, nil
+}
+
+type freshFetchKey struct{}
+
+// withFreshFetch makes the caching fetcher go upstream for every page
+// requested with the returned context, however fresh the cached copy. The
+// new bodies are still stored, and a stale copy is still served when
+// upstream fails.
+func withFreshFetch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshFetchKey{}, true)
+}
+
+func freshFetch(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshFetchKey{}).(bool)
+	return fresh
+}
+
+// cacheUsage collects, per API request, whether upstream data came from the
+// cache and how old the oldest cached piece was.
+type cacheUsage struct {
+	mu     sync.Mutex
+	hits   int
+	misses int
+	oldest time.Time
+}
+
+type cacheUsageKey struct{}
+
+func recordCacheUse(ctx context.Context, hit bool, storedAt time.Time) {
+	u, ok := ctx.Value(cacheUsageKey{}).(*cacheUsage)
+	if !ok {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !hit {
+		u.misses++
+		return
+	}
+	u.hits++
+	if u.oldest.IsZero() || storedAt.Before(u.oldest) {
+		u.oldest = storedAt
+	}
+}
+
+// withCacheUsage returns a context collecting the cache use of everything
+// fetched with it.
+func withCacheUsage(ctx context.Context) (context.Context, *cacheUsage) {
+	usage := &cacheUsage{}
+	return context.WithValue(ctx, cacheUsageKey{}, usage), usage
+}
+
+// dataTime is when the collected data was scraped: the oldest cached piece,
+// or now when everything came from upstream.
+func (u *cacheUsage) dataTime(now time.Time) time.Time {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.hits > 0 {
+		return u.oldest
+	}
+	return now
+}
+
+// cacheHeadersMiddleware adds X-Cache (HIT, MISS or PARTIAL) and Age to
+// responses that used upstream data, so clients can tell how fresh it is.
+func cacheHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, usage := withCacheUsage(r.Context())
+		next.ServeHTTP(&cacheHeaderWriter{ResponseWriter: w, usage: usage}, r.WithContext(ctx))
+	})
+}
+
+type cacheHeaderWriter struct {
+	http.ResponseWriter
+	usage       *cacheUsage
+	wroteHeader bool
+}
+
+func (w *cacheHeaderWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.usage.mu.Lock()
+		hits, misses, oldest := w.usage.hits, w.usage.misses, w.usage.oldest
+		w.usage.mu.Unlock()
+		switch {
+		case hits > 0 && misses == 0:
+			w.Header().Set("X-Cache", "HIT")
+		case hits > 0:
+			w.Header().Set("X-Cache", "PARTIAL")
+		case misses > 0:
+			w.Header().Set("X-Cache", "MISS")
+		}
+		if hits > 0 {
+			w.Header().Set("Age", strconv.Itoa(int(time.Since(oldest).Seconds())))
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheHeaderWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cacheHeaderWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// cachedEntity loads a JSON-encoded entity cached under key if younger than ttl.
+func cachedEntity(ctx context.Context, key string, ttl time.Duration, dst any) bool {
+	if ttl <= 0 {
+		return false
+	}
+	e, ok := responseCache.Get(key)
+	if !ok || time.Since(e.StoredAt) >= ttl {
+		return false
+	}
+	if json.Unmarshal(e.Body, dst) != nil {
+		return false
+	}
+	recordCacheUse(ctx, true, e.StoredAt)
+	return true
+}
+
+// storeEntity caches v as JSON under key.
+func storeEntity(key string, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	responseCache.Set(cacheEntry{Key: key, StoredAt: time.Now(), Body: body})
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubFetcher answers from bodies (errors from errs) and counts the calls per URL.
+type stubFetcher struct {
+	mu     sync.Mutex
+	bodies map[string]string
+	errs   map[string]error
+	calls  map[string]int
+}
+
+func newStubFetcher() *stubFetcher {
+	return &stubFetcher{bodies: map[string]string{}, errs: map[string]error{}, calls: map[string]int{}}
+}
+
+func (f *stubFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[pageURL]++
+	if err := f.errs[pageURL]; err != nil {
+		return nil, err
+	}
+	return []byte(f.bodies[pageURL]), nil
+}
+
+func TestMemoryStoreEvictsOldest(t *testing.T) {
+	m := newMemoryStore(2)
+	now := time.Now()
+	m.Set(cacheEntry{Key: "a", StoredAt: now.Add(-2 * time.Minute)})
+	m.Set(cacheEntry{Key: "b", StoredAt: now.Add(-time.Minute)})
+	m.Set(cacheEntry{Key: "a", StoredAt: now}) // replacing does not evict
+	m.Set(cacheEntry{Key: "c", StoredAt: now})
+	if _, ok := m.Get("b"); ok {
+		t.Error("oldest entry b kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := m.Get(key); !ok {
+			t.Errorf("entry %s evicted", key)
+		}
+	}
+}
+
+func TestDiskStore(t *testing.T) {
+	dir := t.TempDir()
+	d, err := newDiskStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := time.Now().Add(-time.Hour).Round(time.Second)
+	d.Set(cacheEntry{Key: "https://example.test/a", StoredAt: stored, Body: []byte("<html>a</html>")})
+
+	// A new store over the same directory, as after a restart
+	again, _ := newDiskStore(dir)
+	e, ok := again.Get("https://example.test/a")
+	if !ok || string(e.Body) != "<html>a</html>" || !e.StoredAt.Equal(stored) {
+		t.Errorf("Get = %+v, %v", e, ok)
+	}
+	if _, ok := again.Get("https://example.test/b"); ok {
+		t.Error("Get of a missing key succeeded")
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(d.path("https://example.test/a"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	d.Set(cacheEntry{Key: "https://example.test/b", StoredAt: time.Now()})
+	d.prune(24 * time.Hour)
+	if _, ok := d.Get("https://example.test/a"); ok {
+		t.Error("old entry not pruned")
+	}
+	if _, ok := d.Get("https://example.test/b"); !ok {
+		t.Error("recent entry pruned")
+	}
+}
+
+func TestTieredStore(t *testing.T) {
+	disk, _ := newDiskStore(t.TempDir())
+	tiered := &tieredStore{mem: newMemoryStore(10), disk: disk}
+	tiered.Set(cacheEntry{Key: "both", StoredAt: time.Now()})
+	if _, ok := disk.Get("both"); !ok {
+		t.Error("Set did not reach the disk")
+	}
+
+	disk.Set(cacheEntry{Key: "disk", StoredAt: time.Now(), Body: []byte("x")})
+	if e, ok := tiered.Get("disk"); !ok || string(e.Body) != "x" {
+		t.Fatalf("disk entry not found: %+v", e)
+	}
+	if _, ok := tiered.mem.Get("disk"); !ok {
+		t.Error("disk hit not promoted to memory")
+	}
+}
+
+func TestCacheTTLForURL(t *testing.T) {
+	ttls := cacheTTLs{Club: 1, Table: 2, Fixtures: 3, Report: 4, Other: 5}
+	tests := map[string]time.Duration{
+		"https://is.fotbal.cz/public/zapasy/zapis-o-utkani-report.aspx?zapas=x": 4,
+		"https://is.fotbal.cz/public/zapasy/zapas-delegace-report.aspx?zapas=x": 4,
+		"https://is.fotbal.cz/public/souteze/tabulky-souteze.aspx?req=x":        2,
+		"https://is.fotbal.cz/public/souteze/detail-souteze.aspx?req=x":         3,
+		"https://www.fotbal.cz/souteze/turnaje/table/x":                         3,
+		"https://www.fotbal.cz/futsal/club/club/x":                              1,
+		"https://www.fotbal.cz/futsal/club/hledej?q=bizoni":                     1,
+		"https://is.fotbal.cz/public/kluby/detail-klubu.aspx?req=x&rocnik=2025": 5,
+	}
+	for pageURL, want := range tests {
+		if got := ttls.forURL(pageURL); got != want {
+			t.Errorf("forURL(%s) = %d, want %d", pageURL, got, want)
+		}
+	}
+}
+
+func TestCachingFetcher(t *testing.T) {
+	const page = "https://www.fotbal.cz/futsal/club/club/x"
+	ctx := context.Background()
+	upstream := newStubFetcher()
+	upstream.bodies[page] = "v1"
+	store := newMemoryStore(10)
+	c := newCachingFetcher(upstream, store, cacheTTLs{Club: time.Hour})
+
+	fetch := func(ctx context.Context) string {
+		t.Helper()
+		body, err := c.Fetch(ctx, page)
+		if err != nil {
+			t.Fatalf("Fetch: %v", err)
+		}
+		return string(body)
+	}
+	if fetch(ctx) != "v1" || fetch(ctx) != "v1" || upstream.calls[page] != 1 {
+		t.Fatalf("fresh copy not reused: %d upstream calls", upstream.calls[page])
+	}
+
+	upstream.bodies[page] = "v2"
+	if body := fetch(withFreshFetch(ctx)); body != "v2" || upstream.calls[page] != 2 {
+		t.Errorf("fresh fetch = %q after %d upstream calls", body, upstream.calls[page])
+	}
+	if body := fetch(ctx); body != "v2" {
+		t.Errorf("fresh fetch not stored: %q", body)
+	}
+
+	// Expired copies are refetched, and served stale while upstream fails
+	store.Set(cacheEntry{Key: page, StoredAt: time.Now().Add(-2 * time.Hour), Body: []byte("old")})
+	upstream.errs[page] = &upstreamStatusError{URL: page, StatusCode: http.StatusServiceUnavailable}
+	if body := fetch(ctx); body != "old" || upstream.calls[page] != 3 {
+		t.Errorf("stale fallback = %q after %d upstream calls", body, upstream.calls[page])
+	}
+	upstream.errs[page] = &upstreamStatusError{URL: page, StatusCode: http.StatusNotFound}
+	var statusErr *upstreamStatusError
+	if _, err := c.Fetch(ctx, page); !errors.As(err, &statusErr) {
+		t.Errorf("404 answered with a stale copy: %v", err)
+	}
+
+	// A zero TTL bypasses the store
+	const other = "https://example.test/other"
+	upstream.bodies[other] = "x"
+	c.Fetch(ctx, other)
+	c.Fetch(ctx, other)
+	if _, ok := store.Get(other); ok || upstream.calls[other] != 2 {
+		t.Errorf("zero TTL: stored %v, %d upstream calls", ok, upstream.calls[other])
+	}
+}
+
+func TestCacheHeaders(t *testing.T) {
+	tests := []struct {
+		name  string
+		uses  []bool // hit per recorded use
+		cache string
+		age   bool
+	}{
+		{"no upstream data", nil, "", false},
+		{"hit", []bool{true, true}, "HIT", true},
+		{"miss", []bool{false}, "MISS", false},
+		{"partial", []bool{true, false}, "PARTIAL", true},
+	}
+	storedAt := time.Now().Add(-90 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := cacheHeadersMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for _, hit := range tt.uses {
+					recordCacheUse(r.Context(), hit, storedAt)
+				}
+				w.Write([]byte("{}"))
+			}))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			if got := rec.Header().Get("X-Cache"); got != tt.cache {
+				t.Errorf("X-Cache = %q, want %q", got, tt.cache)
+			}
+			age, err := strconv.Atoi(rec.Header().Get("Age"))
+			if tt.age && (err != nil || age < 90) {
+				t.Errorf("Age = %q, want at least 90", rec.Header().Get("Age"))
+			}
+			if !tt.age && rec.Header().Get("Age") != "" {
+				t.Errorf("Age = %q without cached data", rec.Header().Get("Age"))
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// getClubCalendar serves the club's fixtures as an iCalendar (RFC 5545) feed
+func getClubCalendar(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clubID := vars["id"]
+	clubType := vars["type"]
+	if clubID == "" {
+		http.Error(w, "Club ID is required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := sportParamFor(clubType); !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+
+	clubInfo, err := scrapeClubInfo(r.Context(), clubType, clubID, false)
+	if err != nil {
+		writeFetchError(w, "club data", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.ics"`, clubID))
+	w.Write([]byte(clubCalendar(clubInfo, time.Now())))
+}
+
+// clubCalendar renders one VEVENT per match of the club. UIDs are derived
+// from the match ID, so subscribed calendars update rescheduled matches in
+// place instead of duplicating them.
+func clubCalendar(club *ClubInfo, now time.Time) string {
+	var b strings.Builder
+	line := func(name, value string) {
+		writeICSLine(&b, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//facr-scraper//club fixtures//CS")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICSText(club.Name))
+	line("X-WR-TIMEZONE", "Europe/Prague")
+
+	duration := 2 * time.Hour
+	if strings.EqualFold(club.ClubType, "futsal") {
+		duration = 90 * time.Minute
+	}
+	stamp := now.UTC().Format(icsTimeFormat)
+	seen := map[string]bool{}
+	for _, comp := range club.Competitions {
+		for _, m := range comp.Matches {
+			kickoff, ok := m.KickoffTime()
+			if !ok || m.Status == StatusBye {
+				continue
+			}
+			uid := matchUID(comp, m)
+			if seen[uid] {
+				continue
+			}
+			seen[uid] = true
+
+			line("BEGIN", "VEVENT")
+			line("UID", uid)
+			line("DTSTAMP", stamp)
+			status := "CONFIRMED"
+			if m.DateOnly {
+				line("DTSTART;VALUE=DATE", kickoff.Format("20060102"))
+				line("DTEND;VALUE=DATE", kickoff.AddDate(0, 0, 1).Format("20060102"))
+				status = "TENTATIVE"
+			} else {
+				line("DTSTART", kickoff.UTC().Format(icsTimeFormat))
+				line("DTEND", kickoff.Add(duration).UTC().Format(icsTimeFormat))
+			}
+			// A postponed match gets a new date (and the same UID) once rescheduled
+			if m.Status == StatusCancelled || m.Status == StatusPostponed {
+				status = "CANCELLED"
+			}
+			line("STATUS", status)
+			line("SUMMARY", escapeICSText(m.Home+" – "+m.Away))
+			if m.Venue != "" {
+				line("LOCATION", escapeICSText(m.Venue))
+			}
+			if m.FACRLink != "" {
+				line("URL", m.FACRLink)
+			}
+			description := comp.Name
+			if matchPlayed(m, now) {
+				description += "\nVýsledek: " + m.Score
+			}
+			if m.Note != "" {
+				description += "\n" + m.Note
+			}
+			line("DESCRIPTION", escapeICSText(description))
+			line("END", "VEVENT")
+		}
+	}
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// matchUID is a stable event UID; matches without an ID fall back to a hash
+// of competition and teams.
+func matchUID(comp Competition, m Match) string {
+	if m.MatchID != "" {
+		return m.MatchID + "@facr-scraper"
+	}
+	sum := sha1.Sum([]byte(comp.ID + "|" + m.Home + "|" + m.Away + "|" + m.DateTime))
+	return hex.EncodeToString(sum[:]) + "@facr-scraper"
+}
+
+// matchPlayed reports whether m has a result: it was played or forfeited.
+// Matches without a status fall back to kickoff and score.
+func matchPlayed(m Match, now time.Time) bool {
+	switch m.Status {
+	case StatusPlayed, StatusForfeited:
+		return m.Score != ""
+	case "":
+		kickoff, ok := m.KickoffTime()
+		return ok && m.Score != "" && kickoff.Before(now)
+	}
+	return false
+}
+
+// escapeICSText escapes a TEXT value (RFC 5545 section 3.3.11).
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeICSLine writes a content line terminated by CRLF, folding it at 75
+// octets without splitting UTF-8 sequences (RFC 5545 section 3.1).
+func writeICSLine(b *strings.Builder, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// continuation lines start with a space, which counts towards the limit
+		limit = 74
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestClubCalendar(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	played := Match{DateTime: "11.09.2026 20:00", Home: "FC Domov", Away: "SK Hosté; B", Score: "3:1", Status: StatusPlayed, Venue: "SH Domov, hala 2", MatchID: "m1", FACRLink: "https://www.fotbal.cz/m1", Note: `kontumace\oprava`}
+	club := &ClubInfo{
+		Name:     "FC Domov, z.s.",
+		ClubType: "futsal",
+		Competitions: []Competition{
+			{ID: "c1", Name: "Divize, sk. D", Matches: []Match{
+				played,
+				{DateTime: "23.10.2026 19:30", Home: "TJ Třetí", Away: "FC Domov", Status: StatusPostponed, MatchID: "m2", Note: "odloženo"},
+				{DateTime: "30.10.2026", Home: "FC Domov", Away: "TJ Čtvrtí", Status: StatusScheduled, MatchID: "m3"},
+				{DateTime: "06.11.2026 18:00", Home: "FC Domov", Away: "volný los", Status: StatusBye},
+			}},
+			// The same match listed again is one event
+			{ID: "c2", Name: "Pohár", Matches: []Match{played}},
+		},
+	}
+	for _, comp := range club.Competitions {
+		for i, m := range comp.Matches {
+			comp.Matches[i] = withKickoff(m)
+		}
+	}
+	out := clubCalendar(club, now)
+	if !strings.HasSuffix(out, "END:VCALENDAR\r\n") || strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Fatalf("lines are not CRLF terminated:\n%q", out)
+	}
+	if !strings.Contains(out, "\r\nX-WR-CALNAME:FC Domov\\, z.s.\r\n") {
+		t.Errorf("calendar name not escaped:\n%s", out)
+	}
+
+	var events [][]string
+	var event []string
+	for _, line := range strings.Split(out, "\r\n") {
+		switch {
+		case line == "BEGIN:VEVENT":
+			event = []string{}
+		case line == "END:VEVENT":
+			events = append(events, event)
+			event = nil
+		case event != nil:
+			event = append(event, line)
+		}
+	}
+	want := [][]string{
+		{
+			"UID:m1@facr-scraper",
+			"DTSTAMP:20261015T120000Z",
+			"DTSTART:20260911T180000Z",
+			"DTEND:20260911T193000Z",
+			"STATUS:CONFIRMED",
+			`SUMMARY:FC Domov – SK Hosté\; B`,
+			`LOCATION:SH Domov\, hala 2`,
+			"URL:https://www.fotbal.cz/m1",
+			`DESCRIPTION:Divize\, sk. D\nVýsledek: 3:1\nkontumace\\oprava`,
+		},
+		{
+			"UID:m2@facr-scraper",
+			"DTSTAMP:20261015T120000Z",
+			"DTSTART:20261023T173000Z",
+			"DTEND:20261023T190000Z",
+			"STATUS:CANCELLED",
+			"SUMMARY:TJ Třetí – FC Domov",
+			`DESCRIPTION:Divize\, sk. D\nodloženo`,
+		},
+		{
+			"UID:m3@facr-scraper",
+			"DTSTAMP:20261015T120000Z",
+			"DTSTART;VALUE=DATE:20261030",
+			"DTEND;VALUE=DATE:20261031",
+			"STATUS:TENTATIVE",
+			"SUMMARY:FC Domov – TJ Čtvrtí",
+			`DESCRIPTION:Divize\, sk. D`,
+		},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events =\n%q\nwant\n%q", events, want)
+	}
+}
+
+func TestWriteICSLine(t *testing.T) {
+	tests := []string{
+		"SUMMARY:short",
+		"DESCRIPTION:" + strings.Repeat("a", 200),
+		"SUMMARY:" + strings.Repeat("Žluťoučký kůň ", 12),
+	}
+	for _, in := range tests {
+		var b strings.Builder
+		writeICSLine(&b, in)
+		out := b.String()
+		if !strings.HasSuffix(out, "\r\n") {
+			t.Errorf("%q not CRLF terminated", out)
+		}
+		lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+		joined := lines[0]
+		for i, line := range lines {
+			if len(line) > 75 || !utf8.ValidString(line) {
+				t.Errorf("line %d is %d octets or splits a character: %q", i, len(line), line)
+			}
+			if i > 0 {
+				if !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, line)
+				}
+				joined += line[1:]
+			}
+		}
+		if joined != in {
+			t.Errorf("unfolded %q, want %q", joined, in)
+		}
+		if len(in) > 75 && len(lines) < 2 {
+			t.Errorf("%d octets not folded", len(in))
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// errUsage is returned for bad command lines; the usage text has already
+// been printed.
+var errUsage = errors.New("invalid usage")
+
+// usage prints the command line help.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, `Usage:
+  facr-scraper [flags] [serve]                     run the HTTP API (default)
+  facr-scraper [flags] search [-format f] <query>
+  facr-scraper [flags] club [-format f] [-season s] [-officials] <football|futsal> <club-id>
+  facr-scraper [flags] table [-format f] [-season s] [-v2] [-sections s] [-form n] <football|futsal> <club-id>
+  facr-scraper [flags] competition [-format f] [-season s] [-v2] [-sections s] [-form n] <football|futsal> <competition-id>
+  facr-scraper -config <file> export [-out dir] [<football|futsal>:<club-id> ...]
+
+Commands print JSON, or aligned text with -format text. export writes JSON
+snapshots of the clubs listed in the config file ("clubs": [{"type": ..., "id": ...}])
+or on the command line. The same clubs make up the venue directory of the API.
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
+
+// runCommand runs a one-shot scraping command and writes its result to out.
+func runCommand(ctx context.Context, cfg Config, name string, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	format := fs.String("format", "json", "output format: json or text")
+	season := fs.String("season", "", "season to read instead of the current one, e.g. 2024/2025")
+	var officials, v2 *bool
+	var sections *string
+	var form *int
+	switch name {
+	case "search":
+	case "club":
+		officials = fs.Bool("officials", false, "embed referees and officials of every match")
+	case "table", "competition":
+		v2 = fs.Bool("v2", false, "add typed standings rows")
+		sections = fs.String("sections", "all", "standings sections: overall, home, away, other or a section key, comma separated")
+		form = fs.Int("form", 0, "add the last N results of every team")
+	case "export":
+		return runExport(ctx, cfg, fs, args, out)
+	default:
+		fmt.Fprintf(flag.CommandLine.Output(), "unknown command %q\n\n", name)
+		usage()
+		return errUsage
+	}
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *format != "json" && *format != "text" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if *season != "" {
+		normalised, ok := parseSeason(*season)
+		if !ok {
+			return fmt.Errorf("invalid season %q, use e.g. 2024/2025", *season)
+		}
+		ctx = withSeason(ctx, normalised)
+	}
+	// search joins all remaining arguments, so queries need no quoting
+	if (name == "search" && fs.NArg() == 0) || (name != "search" && fs.NArg() != 2) {
+		usage()
+		return errUsage
+	}
+	if name != "search" {
+		if _, ok := sportParamFor(fs.Arg(0)); !ok {
+			return fmt.Errorf("invalid club type %q, use 'football' or 'futsal'", fs.Arg(0))
+		}
+	}
+
+	var result any
+	var text func(io.Writer)
+	switch name {
+	case "search":
+		q := strings.Join(fs.Args(), " ")
+		results, err := searchClubs(ctx, q)
+		if err != nil {
+			return err
+		}
+		result = map[string]any{"query": q, "count": len(results), "results": results}
+		text = func(w io.Writer) { printSearchResults(w, results) }
+	case "club":
+		club, err := scrapeClubInfo(ctx, fs.Arg(0), fs.Arg(1), *officials)
+		if err != nil {
+			return err
+		}
+		result = club
+		text = func(w io.Writer) { printClub(w, club, true, false) }
+	case "table":
+		club, err := scrapeClubTables(ctx, fs.Arg(0), fs.Arg(1), tableOptions{V2: *v2, Sections: parseTableSections(*sections), Form: min(*form, maxFormCount)})
+		if err != nil {
+			return err
+		}
+		result = club
+		text = func(w io.Writer) { printClub(w, club, false, true) }
+	case "competition":
+		info, err := scrapeCompetition(ctx, fs.Arg(0), fs.Arg(1), tableOptions{V2: *v2, Sections: parseTableSections(*sections), Form: min(*form, maxFormCount)})
+		if err != nil {
+			return err
+		}
+		result = info
+		text = func(w io.Writer) { printCompetitionInfo(w, info) }
+	}
+
+	if *format == "text" {
+		tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		text(tw)
+		return tw.Flush()
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// runExport parses the export command line and writes the snapshot.
+func runExport(ctx context.Context, cfg Config, fs *flag.FlagSet, args []string, out io.Writer) error {
+	dir := fs.String("out", "export", "snapshot directory")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	clubs := cfg.Clubs
+	for _, arg := range fs.Args() {
+		clubType, id, ok := strings.Cut(arg, ":")
+		if !ok {
+			usage()
+			return errUsage
+		}
+		clubs = append(clubs, ClubRef{Type: clubType, ID: id})
+	}
+	if len(clubs) == 0 {
+		return errors.New("no clubs to export, list them in the config file or as type:id arguments")
+	}
+	manifest, err := exportSnapshot(ctx, clubs, *dir)
+	if manifest != nil {
+		fmt.Fprintf(out, "wrote %d files to %s\n", len(manifest.Files)+1, *dir)
+	}
+	return err
+}
+
+// exitOnError reports a command failure and exits with status 1 (2 for usage errors).
+func exitOnError(err error) {
+	if err == nil {
+		return
+	}
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
+
+func printSearchResults(w io.Writer, results []SearchResult) {
+	fmt.Fprintln(w, "NAME\tTYPE\tCLUB ID\tADDRESS")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, r.ClubType, r.ClubID, r.Address)
+	}
+}
+
+func printClub(w io.Writer, club *ClubInfo, withMatches, withTables bool) {
+	fmt.Fprintf(w, "%s (%s, %s)\n", club.Name, club.ClubType, club.ClubID)
+	if club.Address != "" {
+		fmt.Fprintln(w, club.Address)
+	}
+	for i := range club.Competitions {
+		comp := &club.Competitions[i]
+		fmt.Fprintf(w, "\n%s [%s]\n", comp.Name, comp.Code)
+		if comp.Error != "" {
+			fmt.Fprintf(w, "  error: %s\n", comp.Error)
+		}
+		if withTables {
+			printTable(w, comp.Table)
+		}
+		if withMatches {
+			printMatches(w, comp.Matches)
+		}
+	}
+}
+
+func printCompetitionInfo(w io.Writer, info *CompetitionInfo) {
+	fmt.Fprintf(w, "%s [%s] %s\n", info.Name, info.Code, info.Season)
+	if info.Error != "" {
+		fmt.Fprintf(w, "error: %s\n", info.Error)
+	}
+	printTable(w, info.Table)
+	fmt.Fprintln(w)
+	printMatches(w, info.Matches)
+}
+
+// printTable prints every standings section that was scraped.
+func printTable(w io.Writer, table *CompetitionTable) {
+	if table == nil {
+		return
+	}
+	sections := []TableSection{{Title: "Overall", Rows: table.Overall}, {Title: "Home", Rows: table.Home}, {Title: "Away", Rows: table.Away}}
+	for _, section := range append(sections, table.Other...) {
+		if section.Rows == nil {
+			continue
+		}
+		fmt.Fprintf(w, "  %s\n", section.Title)
+		printTableRows(w, section.Rows)
+	}
+}
+
+func printTableRows(w io.Writer, rows []TableRow) {
+	fmt.Fprintln(w, "  #\tTEAM\tP\tW\tD\tL\tSCORE\tPTS\tFORM")
+	for _, row := range rows {
+		form := ""
+		if row.Form != nil {
+			form = row.Form.Form
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", row.Rank, row.Team, row.Played, row.Wins, row.Draws, row.Losses, row.Score, row.Points, form)
+	}
+}
+
+func printMatches(w io.Writer, matches []Match) {
+	for _, m := range matches {
+		score := m.Score
+		if m.Note != "" {
+			score = strings.TrimSpace(score + " " + m.Note)
+		} else if score == "" {
+			score = "-"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", m.DateTime, m.Home, score, m.Away, m.Venue)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRunCommandSearch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runCommand(context.Background(), Config{}, "search", []string{"bizoni"}, &buf); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	var got struct {
+		Query   string         `json:"query"`
+		Count   int            `json:"count"`
+		Results []SearchResult `json:"results"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("decode %s: %v", buf.String(), err)
+	}
+	if got.Query != "bizoni" || got.Count == 0 || got.Count != len(got.Results) {
+		t.Errorf("search = %+v, want results for %q", got, "bizoni")
+	}
+
+	// Every remaining argument is part of the query
+	buf.Reset()
+	if err := runCommand(context.Background(), Config{}, "search", []string{"FC", "bizoni"}, &buf); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	got.Query = ""
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("decode %s: %v", buf.String(), err)
+	}
+	if got.Query != "FC bizoni" {
+		t.Errorf("query = %q, want %q", got.Query, "FC bizoni")
+	}
+}
+
+func TestRunCommandText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runCommand(context.Background(), Config{}, "search", []string{"-format", "text", "bizoni"}, &buf); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 2 || strings.Join(strings.Fields(lines[0]), " ") != "NAME TYPE CLUB ID ADDRESS" {
+		t.Fatalf("text output =\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), bizoniID) {
+		t.Errorf("text output lacks club %s:\n%s", bizoniID, buf.String())
+	}
+}
+
+func TestRunCommandUsage(t *testing.T) {
+	flag.CommandLine.SetOutput(io.Discard)
+	defer flag.CommandLine.SetOutput(os.Stderr)
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    string // error text; empty means errUsage
+	}{
+		{"unknown command", "teams", []string{"football", bizoniID}, ""},
+		{"unknown flag", "search", []string{"-officials", "bizoni"}, ""},
+		{"search without query", "search", nil, ""},
+		{"club without id", "club", []string{"football"}, ""},
+		{"table with extra argument", "table", []string{"football", bizoniID, "x"}, ""},
+		{"unknown format", "club", []string{"-format", "xml", "football", bizoniID}, `unknown format "xml"`},
+		{"bad season", "club", []string{"-season", "2024/2026", "football", bizoniID}, "invalid season"},
+		{"bad club type", "club", []string{"hockey", bizoniID}, "invalid club type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := runCommand(context.Background(), Config{}, tt.command, tt.args, &buf)
+			switch {
+			case tt.want == "" && !errors.Is(err, errUsage):
+				t.Errorf("err = %v, want errUsage", err)
+			case tt.want != "" && (err == nil || errors.Is(err, errUsage) || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("wrote %q on error", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gorilla/mux"
+)
+
+// CompetitionInfo is the response of the competition endpoint: metadata,
+// every fixture of the competition (not filtered to a club) and the table.
+type CompetitionInfo struct {
+	Competition
+	Type     string `json:"type"`
+	Season   string `json:"season,omitempty"`
+	TableURL string `json:"table_url"`
+}
+
+var (
+	reCompetitionCode = regexp.MustCompile(`\(([A-Z0-9]{2,5})\)`)
+	reSeasonText      = regexp.MustCompile(`\b(\d{4})\s*/\s*(\d{2,4})\b`)
+)
+
+// getCompetition returns one competition with all of its matches and the overall table
+func getCompetition(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	compID := strings.TrimSpace(vars["competitionID"])
+	clubType := vars["type"]
+	if compID == "" {
+		http.Error(w, "Competition ID is required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := sportParamFor(clubType); !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+
+	ctx, ok := seasonContext(w, r)
+	if !ok {
+		return
+	}
+	opts, ok := tableOptionsFromRequest(w, r)
+	if !ok {
+		return
+	}
+	info, err := scrapeCompetition(ctx, clubType, compID, opts)
+	if err != nil {
+		writeFetchError(w, "competition table", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// scrapeCompetition scrapes metadata, the table and all fixtures of a
+// competition. Only a failing table page is an error; failing fixtures are
+// reported in the Error field. With a season in ctx (see withSeason) the
+// same competition of that season is scraped instead.
+func scrapeCompetition(ctx context.Context, clubType, compID string, opts tableOptions) (*CompetitionInfo, error) {
+	sportParam, ok := sportParamFor(clubType)
+	if !ok {
+		return nil, fmt.Errorf("invalid club type %q", clubType)
+	}
+	if season := seasonFrom(ctx); season != "" {
+		var err error
+		if compID, err = seasonCompetitionID(ctx, clubType, compID, season); err != nil {
+			return nil, err
+		}
+	}
+	info := &CompetitionInfo{
+		Competition: Competition{ID: compID, MatchesLink: fotbalCompetitionURL(clubType, compID)},
+		Type:        clubType,
+		TableURL:    isCompetitionTableURL(compID, sportParam),
+	}
+
+	// The IS table page also carries the competition heading, so it doubles as metadata source
+	docTable, err := fetchDocument(ctx, info.TableURL)
+	if err != nil {
+		return nil, err
+	}
+	info.Table = parseCompetitionTable(ctx, docTable, opts.Sections)
+	info.Season = parseCompetitionMeta(docTable, &info.Competition)
+	// Counted from the page, so ?sections without the overall table keeps it
+	if n := overallTeamCount(docTable); n > 0 {
+		info.TeamCount = fmt.Sprint(n)
+	}
+
+	matches, err := competitionMatches(ctx, info.Competition, clubType, sportParam, "", "")
+	if err != nil {
+		log.Printf("error fetching matches for %s: %v", compID, err)
+		info.Error = err.Error()
+	}
+	info.Matches = matches
+	withForm(info.Table, matches, opts.Form, time.Now())
+	if opts.V2 {
+		withTableV2(info.Table)
+	}
+	return info, nil
+}
+
+// overallTeamCount counts the team rows of the first overall section of an
+// IS table page, without resolving logos as parseCompetitionTable does.
+func overallTeamCount(doc *goquery.Document) int {
+	n := 0
+	doc.Find("h3").EachWithBreak(func(_ int, h3 *goquery.Selection) bool {
+		list := h3.NextUntil("h3").Filter("div.list.tabulky").First()
+		if list.Length() == 0 || tableSectionKey(collapseSpaces(h3.Text())) != "overall" {
+			return true
+		}
+		list.Find("table.vysledky-tabulky tbody tr").Each(func(_ int, tr *goquery.Selection) {
+			if tr.Find("th").Length() == 0 && tr.Find("td").Length() >= 8 {
+				n++
+			}
+		})
+		return false
+	})
+	return n
+}
+
+// parseCompetitionMeta fills name and code of comp from the page heading of
+// an IS competition page and returns the season ("2025/2026") if shown.
+func parseCompetitionMeta(doc *goquery.Document, comp *Competition) string {
+	season := ""
+	doc.Find("h1, h2, title").EachWithBreak(func(_ int, h *goquery.Selection) bool {
+		text := collapseSpaces(h.Text())
+		if m := reSeasonText.FindStringSubmatch(text); m != nil && season == "" {
+			season = m[1] + "/" + m[2]
+			text = strings.Replace(text, m[0], "", 1)
+		}
+		if m := reCompetitionCode.FindStringSubmatch(text); m != nil {
+			if comp.Code == "" {
+				comp.Code = m[1]
+			}
+			text = strings.Replace(text, m[0], "", 1)
+		}
+		// Drop generic page titles such as "Tabulky soutěže - ..."
+		for _, prefix := range []string{"Tabulky soutěže", "Tabulka soutěže", "Detail soutěže", "Soutěž"} {
+			if strings.HasPrefix(text, prefix) {
+				text = strings.TrimLeft(strings.TrimPrefix(text, prefix), " :-–|")
+			}
+		}
+		text = strings.Trim(text, " -–|,")
+		if len([]rune(text)) < 3 {
+			return true
+		}
+		if comp.Name == "" {
+			comp.Name = text
+		}
+		return false
+	})
+	return season
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"log"
+	"regexp"
+	"strconv"
+	"time"
+	_ "time/tzdata" // scraped times are Czech local time; don't depend on the host zoneinfo
+)
+
+// pragueLocation is the timezone all fotbal.cz and IS times are published in.
+var pragueLocation = loadPragueLocation()
+
+func loadPragueLocation() *time.Location {
+	loc, err := time.LoadLocation("Europe/Prague")
+	if err != nil {
+		log.Printf("failed loading Europe/Prague timezone, falling back to UTC: %v", err)
+		return time.UTC
+	}
+	return loc
+}
+
+// reCzechDateTime matches "16.09.2025 20:00", "16. 9. 2025, 20:00", "So 16.09.2025 20.00" and date-only variants.
+var reCzechDateTime = regexp.MustCompile(`(\d{1,2})\.\s*(\d{1,2})\.\s*(\d{4}|\d{2})(?:[\s,]+(\d{1,2})[:.](\d{2}))?`)
+
+// parseKickoff turns the scraped Czech date text into a Prague-local time.
+// dateOnly is set when the time of day is missing or published as 00:00,
+// which upstream uses for "time not yet known".
+func parseKickoff(text string) (kickoff time.Time, dateOnly bool, ok bool) {
+	m := reCzechDateTime.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false, false
+	}
+	day, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	year, _ := strconv.Atoi(m[3])
+	if year < 100 {
+		year += 2000
+	}
+	hour, minute := 0, 0
+	if m[4] != "" {
+		hour, _ = strconv.Atoi(m[4])
+		minute, _ = strconv.Atoi(m[5])
+	}
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 {
+		return time.Time{}, false, false
+	}
+	kickoff = time.Date(year, time.Month(month), day, hour, minute, 0, 0, pragueLocation)
+	// time.Date normalises overflow (31.02. -> 03.03.), reject those
+	if kickoff.Day() != day {
+		return time.Time{}, false, false
+	}
+	return kickoff, hour == 0 && minute == 0, true
+}
+
+// withKickoff fills the parsed kickoff fields of m from its raw DateTime text.
+// A date without a time of day only sets DateOnly: midnight is not a kickoff.
+func withKickoff(m Match) Match {
+	kickoff, dateOnly, ok := parseKickoff(m.DateTime)
+	if !ok {
+		return m
+	}
+	m.DateOnly = dateOnly
+	if !dateOnly {
+		m.Kickoff = kickoff.Format(time.RFC3339)
+		m.KickoffUTC = kickoff.UTC().Format(time.RFC3339)
+	}
+	return m
+}
+
+// KickoffTime returns the parsed kickoff of m, if its date could be parsed.
+// For a date-only match it is midnight of that day, which still orders it
+// among the other matches.
+func (m Match) KickoffTime() (time.Time, bool) {
+	if m.Kickoff == "" {
+		kickoff, _, ok := parseKickoff(m.DateTime)
+		return kickoff, ok
+	}
+	t, err := time.Parse(time.RFC3339, m.Kickoff)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.In(pragueLocation), true
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseKickoff(t *testing.T) {
+	tests := []struct {
+		text     string
+		want     string // RFC 3339, "" when not parsed
+		dateOnly bool
+	}{
+		{"16.09.2025 20:00", "2025-09-16T20:00:00+02:00", false},
+		{"16. 9. 2025, 20:00", "2025-09-16T20:00:00+02:00", false},
+		{"So 16.09.2025 20.00", "2025-09-16T20:00:00+02:00", false},
+		{"07.12.25 18:30", "2025-12-07T18:30:00+01:00", false},
+		{"16.09.2025", "2025-09-16T00:00:00+02:00", true},
+		{"16.09.2025 00:00", "2025-09-16T00:00:00+02:00", true},
+		{"31.02.2025 20:00", "", false},
+		{"16.13.2025", "", false},
+		{"16.09.2025 24:00", "", false},
+		{"bude upřesněno", "", false},
+	}
+	for _, tt := range tests {
+		kickoff, dateOnly, ok := parseKickoff(tt.text)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("parseKickoff(%q) = %v, want no match", tt.text, kickoff)
+			}
+			continue
+		}
+		if !ok || kickoff.Format(time.RFC3339) != tt.want || dateOnly != tt.dateOnly {
+			t.Errorf("parseKickoff(%q) = %v, %v, %v; want %s, %v", tt.text, kickoff.Format(time.RFC3339), dateOnly, ok, tt.want, tt.dateOnly)
+		}
+	}
+}
+
+func TestWithKickoff(t *testing.T) {
+	m := withKickoff(Match{DateTime: "16.09.2025 20:00"})
+	if m.Kickoff != "2025-09-16T20:00:00+02:00" || m.KickoffUTC != "2025-09-16T18:00:00Z" || m.DateOnly {
+		t.Errorf("timed match = %q, %q, %v", m.Kickoff, m.KickoffUTC, m.DateOnly)
+	}
+
+	m = withKickoff(Match{DateTime: "16.09.2025"})
+	if m.Kickoff != "" || m.KickoffUTC != "" || !m.DateOnly {
+		t.Errorf("date-only match = %q, %q, %v; want no kickoff", m.Kickoff, m.KickoffUTC, m.DateOnly)
+	}
+	// Date-only matches still sort by their day
+	if kickoff, ok := m.KickoffTime(); !ok || kickoff.Format("2006-01-02") != "2025-09-16" {
+		t.Errorf("KickoffTime() of date-only match = %v, %v", kickoff, ok)
+	}
+
+	m = withKickoff(Match{DateTime: "odloženo"})
+	if _, ok := m.KickoffTime(); ok || m.Kickoff != "" {
+		t.Errorf("unparsed match = %q, want no kickoff", m.Kickoff)
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	neturl "net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gorilla/mux"
+)
+
+// Official is one person delegated to a match. Role is normalised
+// (referee, assistant_referee, fourth_official, delegate, ...), Label keeps
+// the wording of the IS page.
+type Official struct {
+	Role  string `json:"role"`
+	Label string `json:"label,omitempty"`
+	Name  string `json:"name"`
+	ID    string `json:"id,omitempty"`
+}
+
+// MatchDelegation is the parsed IS delegation report of a match
+type MatchDelegation struct {
+	MatchID           string     `json:"match_id"`
+	DelegationURL     string     `json:"delegation_url"`
+	Referee           *Official  `json:"referee,omitempty"`
+	AssistantReferees []Official `json:"assistant_referees,omitempty"`
+	Delegate          *Official  `json:"delegate,omitempty"`
+	Officials         []Official `json:"officials,omitempty"`
+}
+
+var reOfficialID = regexp.MustCompile(`\(?\b(?:ID\s*)?(\d{6,10})\b\)?`)
+
+// isDelegationURL builds the public IS delegation report URL for a match ID.
+func isDelegationURL(matchID string) string {
+	return isURL("/public/zapasy/zapas-delegace-report.aspx?zapas=%s&hidemenu=1", neturl.QueryEscape(matchID))
+}
+
+// fetchMatchDelegation downloads and parses the IS delegation report of matchID.
+func fetchMatchDelegation(ctx context.Context, matchID string) (*MatchDelegation, error) {
+	delegationURL := isDelegationURL(matchID)
+	doc, err := fetchDocument(ctx, delegationURL)
+	if err != nil {
+		return nil, err
+	}
+	delegation := parseMatchDelegation(doc)
+	delegation.MatchID = matchID
+	delegation.DelegationURL = delegationURL
+	return delegation, nil
+}
+
+// getMatchDelegation returns referees and other officials delegated to a match
+func getMatchDelegation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	matchID := strings.TrimSpace(vars["matchID"])
+	clubType := vars["type"]
+	if matchID == "" {
+		http.Error(w, "Match ID is required", http.StatusBadRequest)
+		return
+	}
+	if clubType != "football" && clubType != "futsal" {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+
+	delegation, err := fetchMatchDelegation(r.Context(), matchID)
+	if err != nil {
+		writeFetchError(w, "match delegation", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(delegation)
+}
+
+// parseMatchDelegation reads role/name pairs from the delegation report. The
+// officials are listed in a table with the role in the first cell and the
+// person in the next one; only the first innermost table listing officials
+// is read, so layout tables wrapping it add nothing. "Role: name" text is
+// accepted as a fallback.
+func parseMatchDelegation(doc *goquery.Document) *MatchDelegation {
+	delegation := &MatchDelegation{}
+	var officials []Official
+	add := func(label, name string) {
+		label = strings.TrimSuffix(collapseSpaces(label), ":")
+		name = collapseSpaces(name)
+		role := officialRole(label)
+		if role == "" || name == "" || name == "-" {
+			return
+		}
+		o := Official{Role: role, Label: label, Name: name}
+		if m := reOfficialID.FindStringSubmatch(name); m != nil {
+			o.ID = m[1]
+			o.Name = strings.TrimSpace(strings.Replace(name, m[0], "", 1))
+		}
+		officials = append(officials, o)
+	}
+
+	doc.Find("table").EachWithBreak(func(_ int, table *goquery.Selection) bool {
+		if table.Find("table").Length() > 0 {
+			return true
+		}
+		table.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+			cells := tr.Children().Filter("td, th")
+			if cells.Length() < 2 {
+				return
+			}
+			add(cells.Eq(0).Text(), cells.Eq(1).Text())
+		})
+		return len(officials) == 0
+	})
+	if len(officials) == 0 {
+		// Labels are kept as written and may hold digits ("AR1", "4. rozhodčí")
+		for _, f := range scanFields(doc) {
+			add(f.label, f.value)
+		}
+	}
+	delegation.Officials = officials
+
+	for i := range delegation.Officials {
+		o := delegation.Officials[i]
+		switch o.Role {
+		case "referee":
+			if delegation.Referee == nil {
+				delegation.Referee = &o
+			}
+		case "assistant_referee":
+			delegation.AssistantReferees = append(delegation.AssistantReferees, o)
+		case "delegate":
+			if delegation.Delegate == nil {
+				delegation.Delegate = &o
+			}
+		}
+	}
+	return delegation
+}
+
+// officialRole maps an IS role label to a normalised role, or "" if the
+// label does not describe an official.
+func officialRole(label string) string {
+	l := foldLabel(label)
+	switch {
+	case l == "":
+		return ""
+	case hasWord(l, "ar1", "ar2", "ar") || strings.Contains(l, "asistent") || strings.Contains(l, "pomezni"):
+		return "assistant_referee"
+	case hasWord(l, "4r", "4.r") || strings.Contains(l, "ctvrty") || strings.HasPrefix(l, "4. rozhod"):
+		return "fourth_official"
+	case hasWord(l, "var", "avar") || strings.Contains(l, "video"):
+		return "var"
+	case strings.Contains(l, "delegat") || hasWord(l, "dfa", "dsk", "dk"):
+		return "delegate"
+	case strings.Contains(l, "casomeric"):
+		return "timekeeper"
+	case strings.Contains(l, "pozorovatel") || strings.Contains(l, "observer"):
+		return "observer"
+	// Futsal delegates up to three referees; the first one leads the match
+	case strings.HasPrefix(l, "2. rozhod") || strings.HasPrefix(l, "3. rozhod"):
+		return "assistant_referee"
+	case strings.Contains(l, "rozhodci") || hasWord(l, "r", "hr"):
+		return "referee"
+	}
+	return ""
+}
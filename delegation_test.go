@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMatchDelegation(t *testing.T) {
+	tests := []struct {
+		name      string
+		html      string
+		officials []Official
+	}{
+		{
+			name: "officials table",
+			html: `<table>
+<tr><td>Rozhodčí:</td><td>Jiří Praporek (87000003)</td></tr>
+<tr><td>AR1:</td><td>Petr Sudí (87000001)</td></tr>
+<tr><td>AR2:</td><td>-</td></tr>
+<tr><td>Delegát:</td><td>Václav Dozor</td></tr>
+</table>`,
+			officials: []Official{
+				{Role: "referee", Label: "Rozhodčí", Name: "Jiří Praporek", ID: "87000003"},
+				{Role: "assistant_referee", Label: "AR1", Name: "Petr Sudí", ID: "87000001"},
+				{Role: "delegate", Label: "Delegát", Name: "Václav Dozor"},
+			},
+		},
+		{
+			name: "layout tables around the officials table",
+			html: `<table><tr>
+<td>Rozhodčí utkání</td>
+<td><table>
+<tr><th>Funkce</th><th>Jméno</th></tr>
+<tr><td>Rozhodčí</td><td>Jiří Praporek</td></tr>
+<tr><td>4. rozhodčí</td><td>Karel Čtvrtý</td></tr>
+</table></td>
+</tr></table>
+<table><tr><td>Delegát svazu</td><td>Zápis schválen</td></tr></table>`,
+			officials: []Official{
+				{Role: "referee", Label: "Rozhodčí", Name: "Jiří Praporek"},
+				{Role: "fourth_official", Label: "4. rozhodčí", Name: "Karel Čtvrtý"},
+			},
+		},
+		{
+			name: "text fallback",
+			html: `<div><b>Rozhodčí:</b> Jiří Praporek</div>
+<div><b>AR1:</b> Petr Sudí (87000001)</div>
+<div><b>4. rozhodčí:</b> Karel Čtvrtý</div>
+<div><b>Delegát:</b> Václav Dozor</div>`,
+			officials: []Official{
+				{Role: "referee", Label: "Rozhodčí", Name: "Jiří Praporek"},
+				{Role: "assistant_referee", Label: "AR1", Name: "Petr Sudí", ID: "87000001"},
+				{Role: "fourth_official", Label: "4. rozhodčí", Name: "Karel Čtvrtý"},
+				{Role: "delegate", Label: "Delegát", Name: "Václav Dozor"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := parseMatchDelegation(parseHTML(t, tt.html))
+			if !reflect.DeepEqual(d.Officials, tt.officials) {
+				t.Errorf("officials = %+v, want %+v", d.Officials, tt.officials)
+			}
+			if d.Referee == nil || d.Referee.Name != "Jiří Praporek" {
+				t.Errorf("referee = %+v", d.Referee)
+			}
+		})
+	}
+}
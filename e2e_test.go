@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// The end-to-end tests run the API router against the fixture directory,
+// like "facr-scraper -fixtures fixtures".
+
+const (
+	bizoniID = "441d3783-06aa-436a-b438-359300ee0371"
+	realTop  = "202216d4-f045-4786-bd21-dd0c9fe34650"
+)
+
+var api http.Handler
+
+// pages counts the page requests of the scrapers; it is installed once as
+// the shared fetcher, in front of the cache.
+var pages *countingFetcher
+
+// countingFetcher counts the pages requested through it; delay (in
+// nanoseconds) slows every request down, so concurrent callers overlap.
+type countingFetcher struct {
+	next  Fetcher
+	count atomic.Int64
+	delay atomic.Int64
+}
+
+func (f *countingFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	f.count.Add(1)
+	time.Sleep(time.Duration(f.delay.Load()))
+	return f.next.Fetch(ctx, pageURL)
+}
+
+func TestMain(m *testing.M) {
+	srv, err := newFixtureServer("fixtures")
+	if err != nil {
+		log.Fatalf("fixtures: %v", err)
+	}
+	upstream = fixtureUpstreams(srv.URL)
+	opts := fetcherOptionsFromEnv()
+	opts.HostDelay = 0
+	opts.Retries = 0
+	responseCache = newMemoryStore(2000)
+	pages = &countingFetcher{next: newCachingFetcher(newHTTPFetcher(opts), responseCache, activeTTLs)}
+	fetcher = pages
+	api = newRouter()
+
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
+
+// getJSON requests path from the API and decodes a 200 answer into v.
+func getJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d: %s", path, rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+}
+
+func getStatus(path string) int {
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code
+}
+
+func TestClubInfoFromFixtures(t *testing.T) {
+	var club ClubInfo
+	getJSON(t, "/club/futsal/"+bizoniID, &club)
+	if club.Name != "FC Bizoni Uherské Hradiště, z.s." || club.ClubInternalID == "" {
+		t.Errorf("club = %q (%q)", club.Name, club.ClubInternalID)
+	}
+	if len(club.Competitions) != 2 {
+		t.Fatalf("%d competitions, want 2", len(club.Competitions))
+	}
+	var played *Match
+	for _, comp := range club.Competitions {
+		if comp.Error != "" || len(comp.Matches) == 0 {
+			t.Errorf("competition %s: %d matches, error %q", comp.ID, len(comp.Matches), comp.Error)
+		}
+		for i, m := range comp.Matches {
+			if m.MatchID == "a9697352-2c4b-21c1-06b3-b0afa194f3f7" {
+				played = &comp.Matches[i]
+			}
+		}
+	}
+	if played == nil {
+		t.Fatal("match a9697352 not listed")
+	}
+	if played.Score != "3:1" || played.Status != StatusPlayed || played.HomeID != bizoniID || played.AwayID != realTop || played.Kickoff != "2026-09-11T20:00:00+02:00" {
+		t.Errorf("match = %+v", *played)
+	}
+}
+
+func TestClubTableFromFixtures(t *testing.T) {
+	var club ClubInfo
+	getJSON(t, "/club/futsal/"+bizoniID+"/table?v=2", &club)
+	for _, comp := range club.Competitions {
+		if comp.Table == nil || len(comp.Table.Overall) == 0 || len(comp.Table.OverallV2) != len(comp.Table.Overall) {
+			t.Errorf("competition %s has no table: %+v", comp.ID, comp.Table)
+		}
+	}
+}
+
+func TestCompetitionSectionsFromFixtures(t *testing.T) {
+	path := "/competition/futsal/42e914ae-0624-4bc1-983e-1f9612c6a1af"
+	var all CompetitionInfo
+	getJSON(t, path, &all)
+	if all.Table == nil || len(all.Table.Overall) == 0 || all.TeamCount != fmt.Sprint(len(all.Table.Overall)) {
+		t.Fatalf("team count %q, table %+v", all.TeamCount, all.Table)
+	}
+
+	var home struct {
+		TeamCount string                     `json:"team_count"`
+		Table     map[string]json.RawMessage `json:"table"`
+	}
+	getJSON(t, path+"?sections=home", &home)
+	if _, ok := home.Table["overall"]; ok || home.Table["home"] == nil {
+		t.Errorf("?sections=home table has %d sections: %v", len(home.Table), home.Table["overall"])
+	}
+	if home.TeamCount != all.TeamCount {
+		t.Errorf("?sections=home team count = %q, want %q", home.TeamCount, all.TeamCount)
+	}
+}
+
+func TestMatchReportFromFixtures(t *testing.T) {
+	var report MatchReport
+	getJSON(t, "/match/futsal/a9697352-2c4b-21c1-06b3-b0afa194f3f7/report", &report)
+	if report.Score != "3:1" || report.Competition != "Super pohár" || report.Attendance != 345 {
+		t.Errorf("report = %q %q %d", report.Score, report.Competition, report.Attendance)
+	}
+	if len(report.Home.Starters) != 5 || len(report.Goals) != 4 {
+		t.Errorf("%d home starters, %d goals; want 5 and 4", len(report.Home.Starters), len(report.Goals))
+	}
+	for _, g := range report.Goals {
+		if g.Side != "home" && g.Side != "away" {
+			t.Errorf("goal without side: %+v", g)
+		}
+	}
+
+	var delegation MatchDelegation
+	getJSON(t, "/match/futsal/a9697352-2c4b-21c1-06b3-b0afa194f3f7/delegation", &delegation)
+	if delegation.Referee == nil || delegation.Referee.Name != "Petr Sudí" || delegation.Referee.ID != "87000001" {
+		t.Errorf("referee = %+v", delegation.Referee)
+	}
+}
+
+func TestHeadToHeadFromFixtures(t *testing.T) {
+	var h2h HeadToHead
+	getJSON(t, "/h2h/futsal/"+bizoniID+"/"+realTop, &h2h)
+	if h2h.Summary.Played != 1 || h2h.Summary.WinsA != 1 || h2h.Summary.GoalsA != 3 || h2h.Summary.GoalsB != 1 {
+		t.Errorf("summary = %+v", h2h.Summary)
+	}
+}
+
+func TestClubSearchFromFixtures(t *testing.T) {
+	var resp struct {
+		Results []SearchResult `json:"results"`
+	}
+	getJSON(t, "/club/search?q=bizoni", &resp)
+	if len(resp.Results) != 1 || resp.Results[0].ClubID != bizoniID {
+		t.Errorf("results = %+v", resp.Results)
+	}
+}
+
+func TestErrorsFromFixtures(t *testing.T) {
+	tests := map[string]int{
+		"/club/hockey/" + bizoniID:                                      http.StatusBadRequest,
+		"/club/futsal/00000000-0000-0000-0000-000000000000":             http.StatusNotFound,
+		"/club/futsal/" + bizoniID + "?season=2024/2026":                http.StatusBadRequest,
+		"/club/futsal/" + bizoniID + "/table?season=2019/2020":          http.StatusNotFound,
+		"/match/futsal/00000000-0000-0000-0000-000000000000/delegation": http.StatusNotFound,
+	}
+	for path, want := range tests {
+		if got := getStatus(path); got != want {
+			t.Errorf("GET %s = %d, want %d", path, got, want)
+		}
+	}
+}
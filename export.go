@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ClubRef names one club in the configuration file.
+type ClubRef struct {
+	Type string `json:"type"` // football or futsal
+	ID   string `json:"id"`
+}
+
+// ExportIndex is index.json of a snapshot: what the snapshot contains and
+// where to find it.
+type ExportIndex struct {
+	GeneratedAt  time.Time           `json:"generated_at"`
+	Clubs        []ExportClub        `json:"clubs"`
+	Competitions []ExportCompetition `json:"competitions"`
+}
+
+// ExportClub lists the files of one exported club.
+type ExportClub struct {
+	Type         string   `json:"type"`
+	ID           string   `json:"id"`
+	Name         string   `json:"name,omitempty"`
+	LogoURL      string   `json:"logo_url,omitempty"`
+	Info         string   `json:"info,omitempty"`   // club info with matches
+	Tables       string   `json:"tables,omitempty"` // club info with standings
+	Competitions []string `json:"competitions,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// ExportCompetition lists the file of one exported competition.
+type ExportCompetition struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Code   string `json:"code,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Season string `json:"season,omitempty"`
+	File   string `json:"file,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ExportManifest is manifest.json of a snapshot: every written file with
+// the time its data was scraped.
+type ExportManifest struct {
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Files      []ManifestFile `json:"files"`
+	Errors     []string       `json:"errors,omitempty"`
+}
+
+// ManifestFile is one file of a snapshot, relative to the snapshot root.
+type ManifestFile struct {
+	Path      string    `json:"path"`
+	ScrapedAt time.Time `json:"scraped_at"`
+	Bytes     int       `json:"bytes"`
+}
+
+// snapshotWriter writes JSON files below dir and records them for the manifest.
+type snapshotWriter struct {
+	dir string
+
+	mu       sync.Mutex
+	manifest ExportManifest
+}
+
+func (s *snapshotWriter) write(rel string, v any, scrapedAt time.Time) error {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(s.dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, append(body, '\n'), 0o644); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.manifest.Files = append(s.manifest.Files, ManifestFile{Path: rel, ScrapedAt: scrapedAt, Bytes: len(body) + 1})
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *snapshotWriter) fail(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	log.Print(msg)
+	s.mu.Lock()
+	s.manifest.Errors = append(s.manifest.Errors, msg)
+	s.mu.Unlock()
+}
+
+// exportSnapshot scrapes every club of clubs and writes a static snapshot to dir:
+//
+//	clubs/<type>/<id>/info.json      club info with matches (as /club/{type}/{id})
+//	clubs/<type>/<id>/tables.json    club info with standings (as /club/{type}/{id}/table)
+//	competitions/<type>/<id>.json    all fixtures and the table (as /competition/{type}/{id})
+//	index.json                       ExportIndex
+//	manifest.json                    ExportManifest
+//
+// Failures of single clubs or competitions are recorded and do not stop the
+// export; the returned error tells how many there were.
+func exportSnapshot(ctx context.Context, clubs []ClubRef, dir string) (*ExportManifest, error) {
+	s := &snapshotWriter{dir: dir}
+	s.manifest.StartedAt = time.Now().UTC()
+	index := ExportIndex{Clubs: []ExportClub{}, Competitions: []ExportCompetition{}}
+
+	type compKey struct{ clubType, id string }
+	seen := map[compKey]bool{}
+	var comps []compKey
+	for _, ref := range clubs {
+		entry := ExportClub{Type: ref.Type, ID: ref.ID}
+		// The ID becomes part of the file path, so only UUIDs are accepted
+		if _, ok := sportParamFor(ref.Type); !ok || !isUUID(ref.ID) {
+			entry.Error = fmt.Sprintf("invalid club %q/%q", ref.Type, ref.ID)
+			s.fail("club %s/%s: %s", ref.Type, ref.ID, entry.Error)
+			index.Clubs = append(index.Clubs, entry)
+			continue
+		}
+		base := path.Join("clubs", ref.Type, ref.ID)
+
+		infoCtx, usage := withCacheUsage(ctx)
+		info, err := scrapeClubInfo(infoCtx, ref.Type, ref.ID, false)
+		if err != nil {
+			entry.Error = err.Error()
+			s.fail("club %s/%s: %v", ref.Type, ref.ID, err)
+			index.Clubs = append(index.Clubs, entry)
+			continue
+		}
+		entry.Name = info.Name
+		entry.LogoURL = info.LogoURL
+		if err := s.write(base+"/info.json", info, usage.dataTime(time.Now()).UTC()); err != nil {
+			return nil, err
+		}
+		entry.Info = base + "/info.json"
+		for _, comp := range info.Competitions {
+			if !isUUID(comp.ID) {
+				if comp.ID != "" {
+					s.fail("club %s/%s: skipping competition with invalid ID %q", ref.Type, ref.ID, comp.ID)
+				}
+				continue
+			}
+			key := compKey{ref.Type, comp.ID}
+			entry.Competitions = append(entry.Competitions, path.Join("competitions", ref.Type, comp.ID+".json"))
+			if !seen[key] {
+				seen[key] = true
+				comps = append(comps, key)
+			}
+		}
+
+		tablesCtx, usage := withCacheUsage(ctx)
+		tables, err := scrapeClubTables(tablesCtx, ref.Type, ref.ID, tableOptions{V2: true})
+		if err != nil {
+			s.fail("club tables %s/%s: %v", ref.Type, ref.ID, err)
+		} else {
+			if err := s.write(base+"/tables.json", tables, usage.dataTime(time.Now()).UTC()); err != nil {
+				return nil, err
+			}
+			entry.Tables = base + "/tables.json"
+		}
+		index.Clubs = append(index.Clubs, entry)
+	}
+
+	// Competitions shared by several clubs are exported once
+	compEntries := make([]ExportCompetition, len(comps))
+	var writeErr error
+	var writeErrOnce sync.Once
+	forEachLimited(len(comps), scrapeConcurrency, func(i int) {
+		key := comps[i]
+		entry := ExportCompetition{Type: key.clubType, ID: key.id}
+		defer func() { compEntries[i] = entry }()
+		compCtx, usage := withCacheUsage(ctx)
+		info, err := scrapeCompetition(compCtx, key.clubType, key.id, tableOptions{V2: true})
+		if err != nil {
+			entry.Error = err.Error()
+			s.fail("competition %s/%s: %v", key.clubType, key.id, err)
+			return
+		}
+		entry.Code, entry.Name, entry.Season = info.Code, info.Name, info.Season
+		if info.Error != "" {
+			entry.Error = info.Error
+			s.fail("competition %s/%s fixtures: %s", key.clubType, key.id, info.Error)
+		}
+		rel := path.Join("competitions", key.clubType, key.id+".json")
+		if err := s.write(rel, info, usage.dataTime(time.Now()).UTC()); err != nil {
+			writeErrOnce.Do(func() { writeErr = err })
+			return
+		}
+		entry.File = rel
+	})
+	if writeErr != nil {
+		return nil, writeErr
+	}
+	index.Competitions = compEntries
+
+	index.GeneratedAt = time.Now().UTC()
+	if err := s.write("index.json", index, index.GeneratedAt); err != nil {
+		return nil, err
+	}
+	s.manifest.FinishedAt = time.Now().UTC()
+	sort.Slice(s.manifest.Files, func(i, j int) bool { return s.manifest.Files[i].Path < s.manifest.Files[j].Path })
+	body, err := json.MarshalIndent(s.manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), append(body, '\n'), 0o644); err != nil {
+		return nil, err
+	}
+	if n := len(s.manifest.Errors); n > 0 {
+		return &s.manifest, fmt.Errorf("export finished with %d error(s), see manifest.json", n)
+	}
+	return &s.manifest, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExportSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	clubs := []ClubRef{
+		{Type: "futsal", ID: bizoniID},
+		{Type: "futsal", ID: "../../escape"},
+	}
+	manifest, err := exportSnapshot(context.Background(), clubs, dir)
+	if err == nil || len(manifest.Errors) != 1 {
+		t.Fatalf("err = %v, errors = %v; want only the invalid club to fail", err, manifest.Errors)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "escape")); !os.IsNotExist(err) {
+		t.Errorf("invalid club ID wrote outside the snapshot: %v", err)
+	}
+
+	files := map[string]ManifestFile{}
+	for _, f := range manifest.Files {
+		files[f.Path] = f
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f.Path))); err != nil {
+			t.Errorf("manifest lists missing file: %v", err)
+		}
+	}
+	for _, want := range []string{
+		"clubs/futsal/" + bizoniID + "/info.json",
+		"clubs/futsal/" + bizoniID + "/tables.json",
+		"competitions/futsal/f49e63bd-55d9-4c5e-93f7-8e482262b88f.json",
+		"index.json",
+	} {
+		if _, ok := files[want]; !ok {
+			t.Errorf("%s not exported", want)
+		}
+	}
+
+	// A second export is served from the cache: scraped_at keeps the time
+	// the pages were fetched
+	again, _ := exportSnapshot(context.Background(), clubs[:1], t.TempDir())
+	for _, f := range again.Files {
+		if f.Path == "index.json" {
+			continue
+		}
+		if first, ok := files[f.Path]; ok && f.ScrapedAt.After(first.ScrapedAt.Add(time.Second)) {
+			t.Errorf("%s scraped_at moved from %s to %s", f.Path, first.ScrapedAt, f.ScrapedAt)
+		}
+		if !f.ScrapedAt.Before(again.StartedAt) {
+			t.Errorf("%s scraped_at %s is not older than the export", f.Path, f.ScrapedAt)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Fetcher downloads upstream pages. Every scraper goes through the shared
+// fetcher below, so it can be swapped for a stand-in in tests or tools.
+type Fetcher interface {
+	// Fetch returns the body of pageURL. Non-200 answers are reported as
+	// *upstreamStatusError.
+	Fetch(ctx context.Context, pageURL string) ([]byte, error)
+}
+
+// fetcher is used by all scrapers.
+var fetcher Fetcher = newHTTPFetcher(fetcherOptionsFromEnv())
+
+// FetcherOptions configures the default HTTP fetcher.
+type FetcherOptions struct {
+	Timeout      time.Duration // per attempt
+	MaxBodyBytes int64         // larger pages are rejected
+	Retries      int           // extra attempts after network errors, 429 and 5xx
+	Backoff      time.Duration // first retry delay, doubled on each further attempt
+	HostDelay    time.Duration // minimum gap between requests to the same host
+}
+
+func defaultFetcherOptions() FetcherOptions {
+	return FetcherOptions{
+		Timeout:      15 * time.Second,
+		MaxBodyBytes: 10 << 20,
+		Retries:      2,
+		Backoff:      500 * time.Millisecond,
+		HostDelay:    200 * time.Millisecond,
+	}
+}
+
+// fetcherOptionsFromEnv applies FETCH_TIMEOUT, FETCH_MAX_BYTES, FETCH_RETRIES,
+// FETCH_BACKOFF and FETCH_HOST_DELAY on top of the defaults.
+func fetcherOptionsFromEnv() FetcherOptions {
+	opts := defaultFetcherOptions()
+	opts.Timeout = envDuration("FETCH_TIMEOUT", opts.Timeout)
+	opts.MaxBodyBytes = int64(envInt("FETCH_MAX_BYTES", int(opts.MaxBodyBytes)))
+	opts.Retries = envInt("FETCH_RETRIES", opts.Retries)
+	opts.Backoff = envDuration("FETCH_BACKOFF", opts.Backoff)
+	opts.HostDelay = envDuration("FETCH_HOST_DELAY", opts.HostDelay)
+	return opts
+}
+
+// httpFetcher is the default Fetcher: browser-like headers (some fotbal.cz
+// pages 404 without them), timeouts, a body size limit, retries with
+// exponential backoff and a per-host request spacing.
+type httpFetcher struct {
+	client *http.Client
+	opts   FetcherOptions
+
+	mu       sync.Mutex
+	nextSlot map[string]time.Time // per host: earliest start of the next request
+}
+
+func newHTTPFetcher(opts FetcherOptions) *httpFetcher {
+	return &httpFetcher{
+		client:   &http.Client{Timeout: opts.Timeout},
+		opts:     opts,
+		nextSlot: map[string]time.Time{},
+	}
+}
+
+var errBodyTooLarge = errors.New("response body too large")
+
+// upstreamStatusError is returned when an upstream page answers with a non-200 status.
+type upstreamStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *upstreamStatusError) Error() string {
+	return fmt.Sprintf("received status code %d from %s", e.StatusCode, e.URL)
+}
+
+func (f *httpFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	u, err := neturl.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	backoff := f.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		body, err := f.fetchOnce(ctx, u)
+		if err == nil || attempt >= f.opts.Retries || !retryable(err) || ctx.Err() != nil {
+			return body, err
+		}
+		log.Printf("fetch %s failed (attempt %d), retrying in %s: %v", pageURL, attempt+1, backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (f *httpFetcher) fetchOnce(ctx context.Context, u *neturl.URL) ([]byte, error) {
+	if err := f.waitForHost(ctx, u.Host); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "cs-CZ,cs;q=0.9,en;q=0.8")
+	req.Header.Set("Referer", u.Scheme+"://"+u.Host+"/")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil, &upstreamStatusError{URL: u.String(), StatusCode: resp.StatusCode}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.opts.MaxBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > f.opts.MaxBodyBytes {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", errBodyTooLarge, u, f.opts.MaxBodyBytes)
+	}
+	return body, nil
+}
+
+// waitForHost blocks until the host's next request slot, keeping at least
+// HostDelay between requests to the same upstream.
+func (f *httpFetcher) waitForHost(ctx context.Context, host string) error {
+	if f.opts.HostDelay <= 0 {
+		return nil
+	}
+	f.mu.Lock()
+	now := time.Now()
+	slot := f.nextSlot[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	f.nextSlot[host] = slot.Add(f.opts.HostDelay)
+	f.mu.Unlock()
+
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryable reports whether a failed attempt may succeed when repeated.
+func retryable(err error) bool {
+	var statusErr *upstreamStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, errBodyTooLarge)
+}
+
+// fetchDocument downloads pageURL through the shared fetcher and parses it as HTML.
+func fetchDocument(ctx context.Context, pageURL string) (*goquery.Document, error) {
+	body, err := fetcher.Fetch(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return goquery.NewDocumentFromReader(bytes.NewReader(body))
+}
+
+// writeFetchError reports a fetchDocument failure to the client, passing
+// upstream status codes through like the club handlers do.
+func writeFetchError(w http.ResponseWriter, what string, err error) {
+	var statusErr *upstreamStatusError
+	if errors.As(err, &statusErr) {
+		http.Error(w, fmt.Sprintf("Error: received status code %d", statusErr.StatusCode), statusErr.StatusCode)
+		return
+	}
+	if errors.Is(err, errSeasonNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, fmt.Sprintf("Error fetching %s: %v", what, err), http.StatusInternalServerError)
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("ignoring invalid %s=%q: %v", name, v, err)
+		return def
+	}
+	return d
+}
+
+// envPositiveDuration is envDuration for intervals: zero and negative values
+// are ignored like malformed ones.
+func envPositiveDuration(name string, def time.Duration) time.Duration {
+	d := envDuration(name, def)
+	if d <= 0 {
+		log.Printf("ignoring non-positive %s=%s", name, d)
+		return def
+	}
+	return d
+}
+
+func envInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("ignoring invalid %s=%q: %v", name, v, err)
+		return def
+	}
+	return n
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testFetcherOptions keeps retries but makes the delays short.
+func testFetcherOptions() FetcherOptions {
+	return FetcherOptions{
+		Timeout:      5 * time.Second,
+		MaxBodyBytes: 1 << 10,
+		Retries:      2,
+		Backoff:      20 * time.Millisecond,
+	}
+}
+
+// recordingServer answers with the statuses in order (200 once they run
+// out) and records when each request arrived.
+func recordingServer(t *testing.T, statuses ...int) (*httptest.Server, func() []time.Time) {
+	t.Helper()
+	var mu sync.Mutex
+	var arrivals []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n := len(arrivals)
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		if n < len(statuses) {
+			w.WriteHeader(statuses[n])
+			return
+		}
+		w.Write([]byte("<html>ok</html>"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Time{}, arrivals...)
+	}
+}
+
+func TestFetcherRetriesWithBackoff(t *testing.T) {
+	srv, arrivals := recordingServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	f := newHTTPFetcher(testFetcherOptions())
+
+	body, err := f.Fetch(context.Background(), srv.URL+"/page")
+	if err != nil || string(body) != "<html>ok</html>" {
+		t.Fatalf("Fetch = %q, %v", body, err)
+	}
+	at := arrivals()
+	if len(at) != 3 {
+		t.Fatalf("%d attempts, want 3", len(at))
+	}
+	// 20ms before the first retry, doubled to 40ms before the second
+	if gap := at[1].Sub(at[0]); gap < 20*time.Millisecond {
+		t.Errorf("first backoff %s, want at least 20ms", gap)
+	}
+	if gap := at[2].Sub(at[1]); gap < 40*time.Millisecond {
+		t.Errorf("second backoff %s, want at least 40ms", gap)
+	}
+}
+
+func TestFetcherGivesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		attempts int
+		status   int
+	}{
+		{"retries exhausted", []int{500, 502, 503, 504}, 3, 503},
+		{"not found is final", []int{404}, 1, 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, arrivals := recordingServer(t, tt.statuses...)
+			_, err := newHTTPFetcher(testFetcherOptions()).Fetch(context.Background(), srv.URL)
+			var statusErr *upstreamStatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+				t.Fatalf("err = %v, want status %d", err, tt.status)
+			}
+			if n := len(arrivals()); n != tt.attempts {
+				t.Errorf("%d attempts, want %d", n, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestFetcherBodyLimit(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(strings.Repeat("x", 2<<10)))
+	}))
+	defer srv.Close()
+
+	_, err := newHTTPFetcher(testFetcherOptions()).Fetch(context.Background(), srv.URL)
+	if !errors.Is(err, errBodyTooLarge) {
+		t.Fatalf("err = %v, want errBodyTooLarge", err)
+	}
+	if requests != 1 {
+		t.Errorf("%d requests, an oversized body must not be retried", requests)
+	}
+}
+
+func TestFetcherHostDelay(t *testing.T) {
+	srv, arrivals := recordingServer(t)
+	opts := testFetcherOptions()
+	opts.HostDelay = 50 * time.Millisecond
+	f := newHTTPFetcher(opts)
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.Fetch(context.Background(), srv.URL); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	at := arrivals()
+	if len(at) != 3 {
+		t.Fatalf("%d requests, want 3", len(at))
+	}
+	if spread := at[2].Sub(at[0]); spread < 100*time.Millisecond-5*time.Millisecond {
+		t.Errorf("3 requests within %s, want them 50ms apart", spread)
+	}
+}
+
+func TestFetcherHostDelayCanceled(t *testing.T) {
+	srv, _ := recordingServer(t)
+	opts := testFetcherOptions()
+	opts.HostDelay = time.Hour
+	f := newHTTPFetcher(opts)
+	if _, err := f.Fetch(context.Background(), srv.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := f.Fetch(ctx, srv.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the context deadline while waiting for the host slot", err)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Recorded upstream pages live in a fixture directory with one subdirectory
+// per origin, mirroring the request path:
+//
+//	fotbal/souteze/club/club/<uuid>.html
+//	is/public/souteze/detail-souteze.aspx@req=<id>&sport=fotbal.html
+//
+// Query parameters are appended after "@" in sorted, URL-encoded form.
+const (
+	fixtureFotbal = "fotbal"
+	fixtureIS     = "is"
+	fixtureMedia  = "media"
+)
+
+// fixtureFile maps a request path and query to a file below the origin's
+// fixture directory.
+func fixtureFile(dir, origin, reqPath string, query neturl.Values) string {
+	p := strings.Trim(path.Clean("/"+reqPath), "/")
+	if p == "" {
+		p = "index"
+	}
+	if len(query) > 0 {
+		p += "@" + query.Encode()
+	}
+	return filepath.Join(dir, origin, filepath.FromSlash(p)+".html")
+}
+
+// fixtureServer serves a fixture directory on a loopback port.
+type fixtureServer struct {
+	URL string
+	srv *http.Server
+}
+
+// newFixtureServer serves recorded pages from dir on a free loopback port.
+// Missing pages answer 404 like an unknown upstream page would.
+// Point the scrapers at it with fixtureUpstreams(srv.URL).
+func newFixtureServer(dir string) (*fixtureServer, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &fixtureServer{URL: "http://" + ln.Addr().String(), srv: &http.Server{Handler: fixtureHandler(dir)}}
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Printf("fixture server: %v", err)
+		}
+	}()
+	return s, nil
+}
+
+func (s *fixtureServer) Close() error {
+	return s.srv.Close()
+}
+
+// fixtureHandler answers requests below /fotbal/, /is/ and /media/ from dir.
+func fixtureHandler(dir string) http.Handler {
+	mux := http.NewServeMux()
+	for _, origin := range []string{fixtureFotbal, fixtureIS, fixtureMedia} {
+		mux.HandleFunc("/"+origin+"/", func(w http.ResponseWriter, r *http.Request) {
+			file := fixtureFile(dir, origin, strings.TrimPrefix(r.URL.Path, "/"+origin), r.URL.Query())
+			body, err := os.ReadFile(file)
+			if err != nil {
+				log.Printf("fixture missing for %s: %s", r.URL, file)
+				http.NotFound(w, r)
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, ".jpg") || strings.HasSuffix(r.URL.Path, ".svg") {
+				w.Header().Set("Content-Type", http.DetectContentType(body))
+			} else {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			}
+			w.Write(body)
+		})
+	}
+	return mux
+}
+
+// fixtureUpstreams are the origins of a fixture server listening on baseURL.
+func fixtureUpstreams(baseURL string) Upstreams {
+	return Upstreams{
+		Fotbal: baseURL + "/" + fixtureFotbal,
+		IS:     baseURL + "/" + fixtureIS,
+		Media:  baseURL + "/" + fixtureMedia,
+	}
+}
+
+// recordingFetcher saves every page fetched from one of the configured
+// origins into a fixture directory, so a live session can be replayed
+// offline with -fixtures.
+type recordingFetcher struct {
+	next Fetcher
+	dir  string
+}
+
+func (f *recordingFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	body, err := f.next.Fetch(ctx, pageURL)
+	if err != nil {
+		return body, err
+	}
+	if file := f.file(pageURL); file != "" {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			log.Printf("recording %s: %v", pageURL, err)
+		} else if err := os.WriteFile(file, body, 0o644); err != nil {
+			log.Printf("recording %s: %v", pageURL, err)
+		}
+	}
+	return body, nil
+}
+
+// file returns the fixture file of pageURL, or "" for foreign origins.
+func (f *recordingFetcher) file(pageURL string) string {
+	u, err := neturl.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	for origin, base := range map[string]string{fixtureFotbal: upstream.Fotbal, fixtureIS: upstream.IS, fixtureMedia: upstream.Media} {
+		b, err := neturl.Parse(base)
+		if err != nil || b.Host != u.Host || !strings.HasPrefix(u.Path, b.Path) {
+			continue
+		}
+		return fixtureFile(f.dir, origin, strings.TrimPrefix(u.Path, b.Path), u.Query())
+	}
+	return ""
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultFormCount = 5
+	maxFormCount     = 20
+)
+
+// TeamForm is the recent record of a team: its last results, newest first.
+type TeamForm struct {
+	Form    string       `json:"form"`             // e.g. "WWDLW", newest first
+	Streak  string       `json:"streak,omitempty"` // current run, e.g. "W3"
+	Results []FormResult `json:"results"`
+}
+
+// FormResult is one played match from the point of view of the team.
+type FormResult struct {
+	Result       string `json:"result"` // W, D or L
+	Opponent     string `json:"opponent"`
+	OpponentID   string `json:"opponent_id,omitempty"`
+	Home         bool   `json:"home"`
+	Score        string `json:"score"` // as published, home goals first
+	GoalsFor     int    `json:"goals_for"`
+	GoalsAgainst int    `json:"goals_against"`
+	Kickoff      string `json:"kickoff,omitempty"`
+	DateTime     string `json:"date_time"`
+	MatchID      string `json:"match_id,omitempty"`
+}
+
+// formCountFromRequest returns how many results ?include=form asks for
+// (?form_count=N, default 5), or 0 when form is not requested. ok is false
+// when form_count is not a positive number.
+func formCountFromRequest(r *http.Request) (n int, ok bool) {
+	include := false
+	for _, v := range r.URL.Query()["include"] {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), "form") {
+				include = true
+			}
+		}
+	}
+	if !include {
+		return 0, true
+	}
+	v := r.URL.Query().Get("form_count")
+	if v == "" {
+		return defaultFormCount, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return min(n, maxFormCount), true
+}
+
+// withForm attaches the last n results to every row of table, computed from
+// the full fixture list of the competition. Home and away sections only count
+// home and away matches respectively.
+func withForm(table *CompetitionTable, matches []Match, n int, now time.Time) {
+	if table == nil || n <= 0 {
+		return
+	}
+	// Played matches, newest first
+	var played []Match
+	for _, m := range matches {
+		if matchPlayed(m, now) {
+			played = append(played, m)
+		}
+	}
+	sort.SliceStable(played, func(i, j int) bool {
+		ti, _ := played[i].KickoffTime()
+		tj, _ := played[j].KickoffTime()
+		return ti.After(tj)
+	})
+
+	apply := func(rows []TableRow, side string) {
+		for i := range rows {
+			rows[i].Form = teamForm(rows[i], played, side, n)
+		}
+	}
+	apply(table.Overall, "")
+	apply(table.Home, "home")
+	apply(table.Away, "away")
+	for i := range table.Other {
+		apply(table.Other[i].Rows, "")
+	}
+}
+
+// teamForm collects the last n results of row's team from played (newest
+// first); the streak looks at all played matches. side restricts to "home"
+// or "away" matches.
+func teamForm(row TableRow, played []Match, side string, n int) *TeamForm {
+	form := &TeamForm{Results: []FormResult{}}
+	for _, m := range played {
+		home := sameTeam(row.TeamID, row.Team, m.HomeID, m.Home)
+		away := !home && sameTeam(row.TeamID, row.Team, m.AwayID, m.Away)
+		if (!home && !away) || (side == "home" && !home) || (side == "away" && !away) {
+			continue
+		}
+		homeGoals, awayGoals, ok := splitScore(m.Score)
+		if !ok {
+			continue
+		}
+		res := FormResult{Home: home, Score: m.Score, Kickoff: m.Kickoff, DateTime: m.DateTime, MatchID: m.MatchID}
+		if home {
+			res.Opponent, res.OpponentID = m.Away, m.AwayID
+			res.GoalsFor, res.GoalsAgainst = homeGoals, awayGoals
+		} else {
+			res.Opponent, res.OpponentID = m.Home, m.HomeID
+			res.GoalsFor, res.GoalsAgainst = awayGoals, homeGoals
+		}
+		switch {
+		case res.GoalsFor > res.GoalsAgainst:
+			res.Result = "W"
+		case res.GoalsFor < res.GoalsAgainst:
+			res.Result = "L"
+		default:
+			res.Result = "D"
+		}
+		form.Results = append(form.Results, res)
+	}
+	if len(form.Results) > 0 {
+		streak := 1
+		for streak < len(form.Results) && form.Results[streak].Result == form.Results[0].Result {
+			streak++
+		}
+		form.Streak = form.Results[0].Result + strconv.Itoa(streak)
+	}
+	form.Results = form.Results[:min(n, len(form.Results))]
+	for _, res := range form.Results {
+		form.Form += res.Result
+	}
+	return form
+}
+
+// sameTeam matches a team by UUID when both sides have one, by name otherwise.
+func sameTeam(idA, nameA, idB, nameB string) bool {
+	if idA != "" && idB != "" {
+		return strings.EqualFold(idA, idB)
+	}
+	return nameA != "" && foldLabel(nameA) == foldLabel(nameB)
+}
+
+// splitScore parses a "home:away" score.
+func splitScore(score string) (int, int, bool) {
+	h, a, ok := strings.Cut(score, ":")
+	if !ok {
+		return 0, 0, false
+	}
+	home, errH := strconv.Atoi(strings.TrimSpace(h))
+	away, errA := strconv.Atoi(strings.TrimSpace(a))
+	if errH != nil || errA != nil {
+		return 0, 0, false
+	}
+	return home, away, true
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTeamForm(t *testing.T) {
+	// Newest first, as withForm passes them
+	played := []Match{
+		{DateTime: "10.10.2026 18:00", Home: "SK Hosté", HomeID: "b", Away: "FC Domov", AwayID: "a", Score: "1:3", MatchID: "m5"},
+		{DateTime: "03.10.2026 18:00", Home: "FC Domov", HomeID: "a", Away: "TJ Třetí", AwayID: "c", Score: "2:0", MatchID: "m4"},
+		{DateTime: "26.09.2026 18:00", Home: "TJ Třetí", HomeID: "c", Away: "FC Domov", AwayID: "a", Score: "0:1", MatchID: "m3"},
+		{DateTime: "19.09.2026 18:00", Home: "FC Domov", HomeID: "a", Away: "SK Hosté", AwayID: "b", Score: "1:1", MatchID: "m2"},
+		{DateTime: "12.09.2026 18:00", Home: "SK Hosté", HomeID: "b", Away: "FC Domov", AwayID: "a", Score: "4:0", MatchID: "m1"},
+		{DateTime: "05.09.2026 18:00", Home: "SK Hosté", HomeID: "b", Away: "TJ Třetí", AwayID: "c", Score: "2:2", MatchID: "m0"},
+	}
+	domov := TableRow{Team: "FC Domov", TeamID: "a"}
+
+	tests := []struct {
+		name   string
+		row    TableRow
+		side   string
+		n      int
+		form   string
+		streak string
+	}{
+		{"all matches", domov, "", 5, "WWWDL", "W3"},
+		{"truncated, streak over all matches", domov, "", 2, "WW", "W3"},
+		{"home section", domov, "home", 5, "WD", "W1"},
+		{"away section", domov, "away", 5, "WWL", "W2"},
+		{"matched by name without ID", TableRow{Team: "tj treti"}, "", 5, "LLD", "L2"},
+		{"team without matches", TableRow{Team: "Nikdo", TeamID: "z"}, "", 5, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := teamForm(tt.row, played, tt.side, tt.n)
+			if f.Form != tt.form || f.Streak != tt.streak || len(f.Results) != len(tt.form) {
+				t.Errorf("form, streak = %q, %q (%d results); want %q, %q", f.Form, f.Streak, len(f.Results), tt.form, tt.streak)
+			}
+		})
+	}
+
+	// Away: goals are counted from the team's side of the score
+	f := teamForm(domov, played, "", 1)
+	want := FormResult{Result: "W", Opponent: "SK Hosté", OpponentID: "b", Home: false, Score: "1:3", GoalsFor: 3, GoalsAgainst: 1, DateTime: "10.10.2026 18:00", MatchID: "m5"}
+	if len(f.Results) != 1 || f.Results[0] != want {
+		t.Errorf("away result = %+v, want %+v", f.Results, want)
+	}
+}
+
+func TestWithForm(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, pragueLocation)
+	matches := []Match{
+		{DateTime: "19.09.2026 18:00", Home: "FC Domov", Away: "SK Hosté", Score: "1:1"},
+		{DateTime: "10.10.2026 18:00", Home: "SK Hosté", Away: "FC Domov", Score: "1:3"},
+		{DateTime: "24.10.2026 18:00", Home: "FC Domov", Away: "SK Hosté", Score: "-:-"}, // not played yet
+	}
+	table := &CompetitionTable{
+		Overall: []TableRow{{Team: "FC Domov"}},
+		Home:    []TableRow{{Team: "FC Domov"}},
+		Away:    []TableRow{{Team: "FC Domov"}},
+	}
+	withForm(table, matches, 5, now)
+	for _, tt := range []struct {
+		section string
+		row     TableRow
+		form    string
+	}{
+		{"overall", table.Overall[0], "WD"},
+		{"home", table.Home[0], "D"},
+		{"away", table.Away[0], "W"},
+	} {
+		if tt.row.Form == nil || tt.row.Form.Form != tt.form {
+			t.Errorf("%s form = %+v, want %q", tt.section, tt.row.Form, tt.form)
+		}
+	}
+}
+
+func TestFormCountFromRequest(t *testing.T) {
+	tests := []struct {
+		query string
+		n     int
+		ok    bool
+	}{
+		{"", 0, true},
+		{"form_count=3", 0, true},
+		{"include=form", defaultFormCount, true},
+		{"include=v2,form&form_count=3", 3, true},
+		{"include=form&form_count=100", maxFormCount, true},
+		{"include=form&form_count=0", 0, false},
+		{"include=form&form_count=x", 0, false},
+	}
+	for _, tt := range tests {
+		r := &http.Request{URL: &url.URL{RawQuery: tt.query}}
+		if n, ok := formCountFromRequest(r); n != tt.n || ok != tt.ok {
+			t.Errorf("formCountFromRequest(%q) = %d, %v; want %d, %v", tt.query, n, ok, tt.n, tt.ok)
+		}
+	}
+	if code := getStatus("/club/futsal/" + bizoniID + "/table?include=form&form_count=x"); code != http.StatusBadRequest {
+		t.Errorf("bad form_count = %d, want 400", code)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// HeadToHead is the response of the head-to-head endpoint.
+type HeadToHead struct {
+	Type         string           `json:"type"`
+	ClubA        H2HClub          `json:"club_a"`
+	ClubB        H2HClub          `json:"club_b"`
+	Summary      H2HSummary       `json:"summary"`
+	Matches      []H2HMatch       `json:"matches"`
+	Competitions []H2HCompetition `json:"competitions"`
+}
+
+// H2HClub identifies one side of a head-to-head.
+type H2HClub struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	LogoURL string `json:"logo_url,omitempty"`
+}
+
+// H2HSummary aggregates the played matches from the point of view of club A.
+type H2HSummary struct {
+	Played   int `json:"played"`
+	WinsA    int `json:"wins_a"`
+	Draws    int `json:"draws"`
+	WinsB    int `json:"wins_b"`
+	GoalsA   int `json:"goals_a"`
+	GoalsB   int `json:"goals_b"`
+	Upcoming int `json:"upcoming"`
+}
+
+// H2HMatch is a match between the two clubs with its competition.
+type H2HMatch struct {
+	Match
+	CompetitionID   string `json:"competition_id"`
+	CompetitionName string `json:"competition_name,omitempty"`
+	Played          bool   `json:"played"`
+}
+
+// H2HCompetition is a competition both clubs play in.
+type H2HCompetition struct {
+	ID    string `json:"id"`
+	Code  string `json:"code,omitempty"`
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// getHeadToHead returns all matches between two clubs in the competitions they share
+func getHeadToHead(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clubType := vars["type"]
+	clubA := strings.TrimSpace(vars["clubA"])
+	clubB := strings.TrimSpace(vars["clubB"])
+	if clubA == "" || clubB == "" {
+		http.Error(w, "Club ID is required", http.StatusBadRequest)
+		return
+	}
+	if strings.EqualFold(clubA, clubB) {
+		http.Error(w, "Two different clubs are required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := sportParamFor(clubType); !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+
+	h2h, err := headToHead(r.Context(), clubType, clubA, clubB, time.Now())
+	if err != nil {
+		writeFetchError(w, "club data", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h2h)
+}
+
+// headToHead finds the matches between two clubs. Every shared competition
+// is parsed once without a club filter, so home_id and away_id come only
+// from the team links and never from fuzzy name matching; a match is
+// between the two clubs when those IDs are theirs.
+func headToHead(ctx context.Context, clubType, clubA, clubB string, now time.Time) (*HeadToHead, error) {
+	var pages [2]*ClubInfo
+	var errs [2]error
+	forEachLimited(2, 2, func(i int) {
+		pages[i], errs[i] = scrapeClubPage(ctx, clubType, []string{clubA, clubB}[i])
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	a, b := pages[0], pages[1]
+	sportParam, _ := sportParamFor(clubType)
+
+	inB := map[string]bool{}
+	for _, comp := range b.Competitions {
+		inB[comp.ID] = true
+	}
+	var shared []Competition
+	for _, comp := range a.Competitions {
+		if comp.ID != "" && inB[comp.ID] {
+			shared = append(shared, comp)
+		}
+	}
+
+	h2h := &HeadToHead{
+		Type:         clubType,
+		ClubA:        H2HClub{ID: clubA, Name: a.Name, LogoURL: a.LogoURL},
+		ClubB:        H2HClub{ID: clubB, Name: b.Name, LogoURL: b.LogoURL},
+		Matches:      []H2HMatch{},
+		Competitions: make([]H2HCompetition, len(shared)),
+	}
+	found := make([][]H2HMatch, len(shared))
+	forEachLimited(len(shared), scrapeConcurrency, func(i int) {
+		comp := shared[i]
+		h2h.Competitions[i] = H2HCompetition{ID: comp.ID, Code: comp.Code, Name: comp.Name}
+		matches, err := competitionMatches(ctx, comp, clubType, sportParam, "", "")
+		if err != nil {
+			log.Printf("error fetching head-to-head matches for %s: %v", comp.ID, err)
+			h2h.Competitions[i].Error = err.Error()
+			return
+		}
+		for _, m := range matches {
+			if !pairsClubs(m, clubA, clubB) {
+				continue
+			}
+			found[i] = append(found[i], H2HMatch{Match: m, CompetitionID: comp.ID, CompetitionName: comp.Name, Played: matchPlayed(m, now)})
+		}
+	})
+	for _, matches := range found {
+		h2h.Matches = append(h2h.Matches, matches...)
+	}
+	sort.SliceStable(h2h.Matches, func(i, j int) bool {
+		ti, _ := h2h.Matches[i].KickoffTime()
+		tj, _ := h2h.Matches[j].KickoffTime()
+		return ti.Before(tj)
+	})
+
+	for _, m := range h2h.Matches {
+		if !m.Played {
+			if m.Status == StatusScheduled {
+				h2h.Summary.Upcoming++
+			}
+			continue
+		}
+		home, away, ok := splitScore(m.Score)
+		if !ok {
+			continue
+		}
+		goalsA, goalsB := home, away
+		if strings.EqualFold(m.AwayID, clubA) {
+			goalsA, goalsB = away, home
+		}
+		h2h.Summary.Played++
+		h2h.Summary.GoalsA += goalsA
+		h2h.Summary.GoalsB += goalsB
+		switch {
+		case goalsA > goalsB:
+			h2h.Summary.WinsA++
+		case goalsA < goalsB:
+			h2h.Summary.WinsB++
+		default:
+			h2h.Summary.Draws++
+		}
+	}
+	return h2h, nil
+}
+
+// pairsClubs reports whether m is played between clubA and clubB, by UUID.
+func pairsClubs(m Match, clubA, clubB string) bool {
+	home, away := strings.ToLower(m.HomeID), strings.ToLower(m.AwayID)
+	a, b := strings.ToLower(clubA), strings.ToLower(clubB)
+	return (home == a && away == b) || (home == b && away == a)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logo sources tell clients how a logo URL was obtained.
+const (
+	logoSourceTeamID      = "team_id"      // built from the club UUID
+	logoSourceSearchExact = "search_exact" // club search hit with the same name
+	logoSourceSearchFuzzy = "search_fuzzy" // best club search hit with a similar name
+	logoSourcePlaceholder = "placeholder"  // nothing found, generic club logo
+)
+
+// logoResult is a resolved team logo.
+type logoResult struct {
+	URL    string `json:"url"`
+	Source string `json:"source"`
+}
+
+// logoLookups deduplicates concurrent club searches for the same team name,
+// which is common when competitions of one club are scraped in parallel.
+var logoLookups = struct {
+	sync.Mutex
+	inflight map[string]*logoLookup
+}{inflight: map[string]*logoLookup{}}
+
+type logoLookup struct {
+	done   chan struct{}
+	result logoResult
+}
+
+// resolveLogo returns the logo of a team. A team ID is turned into the
+// official IS logo URL directly; otherwise the club search is run in-process
+// and its answer kept in the response cache under "logo:<name>".
+func resolveLogo(ctx context.Context, teamName, teamID string) logoResult {
+	name := strings.ToLower(strings.TrimSpace(teamName))
+	if name == "" || isByeTeam(name) {
+		return placeholderLogo()
+	}
+	// If we have a team ID, construct the official logo URL directly.
+	// This avoids wrong matches for duplicate names (e.g., multiple "Ořechov").
+	if tid := strings.TrimSpace(teamID); tid != "" {
+		return logoResult{URL: clubLogoURL(tid), Source: logoSourceTeamID}
+	}
+
+	key := "logo:" + name
+	if res, ok := cachedLogo(key); ok {
+		return res
+	}
+
+	logoLookups.Lock()
+	if l, ok := logoLookups.inflight[key]; ok {
+		logoLookups.Unlock()
+		select {
+		case <-l.done:
+			return l.result
+		case <-ctx.Done():
+			return placeholderLogo()
+		}
+	}
+	l := &logoLookup{done: make(chan struct{})}
+	logoLookups.inflight[key] = l
+	logoLookups.Unlock()
+
+	res, err := searchLogo(ctx, strings.TrimSpace(teamName))
+	if err != nil {
+		log.Printf("logo search for %q failed: %v", teamName, err)
+	} else {
+		storeEntity(key, res)
+	}
+	l.result = res
+
+	logoLookups.Lock()
+	delete(logoLookups.inflight, key)
+	logoLookups.Unlock()
+	close(l.done)
+	return res
+}
+
+func placeholderLogo() logoResult {
+	return logoResult{URL: fotbalURL("/dist/img/logo-club-empty.svg"), Source: logoSourcePlaceholder}
+}
+
+// cachedLogo reads a logo stored by resolveLogo. Placeholders expire sooner
+// so teams that get a club page later are picked up.
+func cachedLogo(key string) (logoResult, bool) {
+	var res logoResult
+	e, ok := responseCache.Get(key)
+	if !ok || json.Unmarshal(e.Body, &res) != nil {
+		return res, false
+	}
+	ttl := activeTTLs.Logo
+	if res.Source == logoSourcePlaceholder {
+		ttl = min(ttl, 24*time.Hour)
+	}
+	return res, time.Since(e.StoredAt) < ttl
+}
+
+// searchLogo looks the team up with the club search, first by its
+// simplified name (e.g. "krnov") and then by the full name, and picks the
+// best hit: exact name, then a name containing the other, else the first one.
+func searchLogo(ctx context.Context, name string) (logoResult, error) {
+	placeholder := placeholderLogo()
+	query := simplifyClubQuery(name)
+	if query == "" {
+		query = name
+	}
+	results, err := searchClubs(ctx, query)
+	if err == nil && len(results) == 0 && query != name {
+		// Fallback to full name if simplified token yields nothing
+		results, err = searchClubs(ctx, name)
+	}
+	if err != nil {
+		return placeholder, err
+	}
+
+	for _, r := range results {
+		if strings.EqualFold(strings.TrimSpace(r.Name), name) && r.LogoURL != "" {
+			return logoResult{URL: r.LogoURL, Source: logoSourceSearchExact}, nil
+		}
+	}
+	key := strings.ToLower(name)
+	for _, r := range results {
+		rname := strings.ToLower(strings.TrimSpace(r.Name))
+		if rname != "" && r.LogoURL != "" && (strings.Contains(rname, key) || strings.Contains(key, rname)) {
+			return logoResult{URL: r.LogoURL, Source: logoSourceSearchFuzzy}, nil
+		}
+	}
+	if len(results) > 0 && results[0].LogoURL != "" {
+		return logoResult{URL: results[0].LogoURL, Source: logoSourceSearchFuzzy}, nil
+	}
+	return placeholder, nil
+}
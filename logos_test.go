@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResolveLogoSharesSearches(t *testing.T) {
+	pages.delay.Store(int64(50 * time.Millisecond))
+	defer pages.delay.Store(0)
+
+	// The club search of "Futsal Bizoni" asks for "bizoni", one page
+	const lookups = 8
+	before := pages.count.Load()
+	results := make([]logoResult, lookups)
+	var start, wg sync.WaitGroup
+	start.Add(1)
+	for i := range lookups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start.Wait()
+			results[i] = resolveLogo(context.Background(), "Futsal Bizoni", "")
+		}()
+	}
+	start.Done()
+	wg.Wait()
+	if n := pages.count.Load() - before; n != 1 {
+		t.Errorf("%d concurrent lookups fetched %d pages, want 1", lookups, n)
+	}
+	for _, res := range results {
+		if res != results[0] || res.Source == logoSourcePlaceholder {
+			t.Errorf("results = %+v", results)
+			break
+		}
+	}
+
+	// Later lookups are answered from the cache
+	before = pages.count.Load()
+	if res := resolveLogo(context.Background(), " futsal bizoni ", ""); res != results[0] {
+		t.Errorf("cached lookup = %+v, want %+v", res, results[0])
+	}
+	if n := pages.count.Load() - before; n != 0 {
+		t.Errorf("cached lookup fetched %d pages", n)
+	}
+
+	// A team ID needs no search at all
+	if res := resolveLogo(context.Background(), "Whatever", bizoniID); res.Source != logoSourceTeamID || pages.count.Load() != before {
+		t.Errorf("team ID lookup = %+v", res)
+	}
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -25,37 +28,20 @@ type Competition struct {
 	MatchesLink string            `json:"matches_link"`
 	Matches     []Match           `json:"matches,omitempty"`
 	Table       *CompetitionTable `json:"table,omitempty"`
+	Error       string            `json:"error,omitempty"` // set when this competition could not be scraped
 }
 
 // parseCompetitionMatchesFromFotbal scrapes matches from the public fotbal.cz
 // competition page (e.g., https://www.fotbal.cz/souteze/turnaje/table/{id}).
 // It filters to only include matches involving the given clubName if provided.
-func parseCompetitionMatchesFromFotbal(pageURL, clubType, clubName, clubID string) []Match {
+func parseCompetitionMatchesFromFotbal(ctx context.Context, pageURL, clubType, clubName, clubID string) ([]Match, error) {
     pageURL = strings.TrimSpace(pageURL)
     if pageURL == "" {
-        return nil
+        return nil, nil
     }
-    // Request with browser-like headers; some fotbal.cz pages 404 without them
-    req, _ := http.NewRequest("GET", pageURL, nil)
-    req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36")
-    req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8")
-    req.Header.Set("Accept-Language", "cs-CZ,cs;q=0.9,en;q=0.8")
-    client := &http.Client{Timeout: 15 * time.Second}
-    resp, err := client.Do(req)
+    body, err := fetcher.Fetch(ctx, pageURL)
     if err != nil {
-        log.Printf("fotbal.cz matches fetch error for %s: %v", pageURL, err)
-        return nil
-    }
-    defer resp.Body.Close()
-    if resp.StatusCode != http.StatusOK {
-        log.Printf("fotbal.cz matches non-200 for %s: %d", pageURL, resp.StatusCode)
-        return nil
-    }
-    // Read body to optionally save and to allow multiple reads
-    body, err := io.ReadAll(resp.Body)
-    if err != nil {
-        log.Printf("fotbal.cz matches read error for %s: %v", pageURL, err)
-        return nil
+        return nil, fmt.Errorf("fotbal.cz matches fetch error for %s: %w", pageURL, err)
     }
     // Debug: save full HTML if env toggled
     if os.Getenv("DEBUG_SAVE_HTML") != "" {
@@ -73,8 +59,7 @@ func parseCompetitionMatchesFromFotbal(pageURL, clubType, clubName, clubID strin
     }
     doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
     if err != nil {
-        log.Printf("fotbal.cz matches parse error for %s: %v", pageURL, err)
-        return nil
+        return nil, fmt.Errorf("fotbal.cz matches parse error for %s: %w", pageURL, err)
     }
 
     var matches []Match
@@ -111,6 +96,7 @@ func parseCompetitionMatchesFromFotbal(pageURL, clubType, clubName, clubID strin
         if len(imgIDs) >= 2 { awayID = imgIDs[1] }
         // Score
         score := strings.TrimSpace(a.Find("strong.H4").First().Text())
+        marker := score
         if re := regexp.MustCompile(`\s*([0-9]+)\s*:\s*([0-9]+)\s*`); re != nil {
             if m := re.FindStringSubmatch(score); len(m) == 3 {
                 score = fmt.Sprintf("%s:%s", m[1], m[2])
@@ -124,6 +110,9 @@ func parseCompetitionMatchesFromFotbal(pageURL, clubType, clubName, clubID strin
             if strings.HasPrefix(strings.ToLower(label), "datum") {
                 // Remove label from text
                 dateText = strings.TrimSpace(strings.ReplaceAll(txt, label+":", ""))
+            } else if strings.HasPrefix(foldLabel(label), "poznamka") || strings.HasPrefix(foldLabel(label), "stav") {
+                // Postponement and forfeit notes
+                marker += " " + strings.TrimSpace(strings.ReplaceAll(txt, label+":", ""))
             }
         })
         // Venue from details, if available
@@ -137,14 +126,7 @@ func parseCompetitionMatchesFromFotbal(pageURL, clubType, clubName, clubID strin
         })
         // Match ID from the anchor href
         matchID := extractUUIDFromHref(a.AttrOr("href", ""))
-        reportURL := ""
-        if matchID != "" {
-            if strings.EqualFold(clubType, "futsal") {
-                reportURL = fmt.Sprintf("https://www.fotbal.cz/futsal/zapasy/futsal/%s", matchID)
-            } else {
-                reportURL = fmt.Sprintf("https://www.fotbal.cz/souteze/zapasy/zapas/%s", matchID)
-            }
-        }
+        reportURL := facrMatchURL(clubType, matchID)
         // Filter by club involvement: prefer UUID match, fallback to name matching including simplified token
         if clubName != "" || clubID != "" {
             involved := false
@@ -187,39 +169,50 @@ func parseCompetitionMatchesFromFotbal(pageURL, clubType, clubName, clubID strin
                 }
             }
         }
-        homeLogo := getLogo(home, homeID)
-        awayLogo := getLogo(away, awayID)
-        matches = append(matches, Match{
+        homeLogo := resolveLogo(ctx, home, homeID)
+        awayLogo := resolveLogo(ctx, away, awayID)
+        matches = append(matches, withStatus(withKickoff(Match{
             DateTime: dateText,
-            Home: home, HomeID: homeID, HomeLogoURL: homeLogo,
-            Away: away, AwayID: awayID, AwayLogoURL: awayLogo,
+            Home: home, HomeID: homeID, HomeLogoURL: homeLogo.URL, HomeLogoSource: homeLogo.Source,
+            Away: away, AwayID: awayID, AwayLogoURL: awayLogo.URL, AwayLogoSource: awayLogo.Source,
             Score: score,
             Venue: venue,
             MatchID: matchID,
             ReportURL: reportURL,
             FACRLink:  reportURL,
-        })
+        }), marker, time.Now()))
     })
-    return matches
+    return matches, nil
+}
+
+// competitionMatches collects the fixtures of one competition from fotbal.cz and IS.
+// With empty clubName and clubID the whole competition is returned.
+// An error is returned only when neither source could be read.
+func competitionMatches(ctx context.Context, comp Competition, clubType, sportParam, clubName, clubID string) ([]Match, error) {
+	// 1) Try parsing from the public fotbal.cz competition page (matches_link)
+	matches, fotbalErr := parseCompetitionMatchesFromFotbal(ctx, comp.MatchesLink, clubType, clubName, clubID)
+	// Always try IS as well
+	isMatches, isErr := parseCompetitionMatchesFromIS(ctx, isCompetitionDetailURL(comp.ID, sportParam), clubType, clubName, clubID)
+	// Prefer IS whenever it yields any results, as IS often contains alias team names
+	if len(isMatches) > 0 {
+		return isMatches, nil
+	}
+	if fotbalErr != nil && isErr != nil {
+		return nil, errors.Join(fotbalErr, isErr)
+	}
+	for _, err := range []error{fotbalErr, isErr} {
+		if err != nil {
+			log.Printf("%v", err)
+		}
+	}
+	return matches, nil
 }
 
 // parseCompetitionMatchesFromIS scrapes matches from the IS portal as fallback.
-func parseCompetitionMatchesFromIS(detailURL, clubType, clubName, clubID string) []Match {
-    resp, err := http.Get(detailURL)
-    if err != nil {
-        log.Printf("IS matches fetch error for %s: %v", detailURL, err)
-        return nil
-    }
-    defer resp.Body.Close()
-    if resp.StatusCode != http.StatusOK {
-        log.Printf("IS matches non-200 for %s: %d", detailURL, resp.StatusCode)
-        return nil
-    }
-    // Read body so we can optionally save and then parse from memory
-    body, err := io.ReadAll(resp.Body)
+func parseCompetitionMatchesFromIS(ctx context.Context, detailURL, clubType, clubName, clubID string) ([]Match, error) {
+    body, err := fetcher.Fetch(ctx, detailURL)
     if err != nil {
-        log.Printf("IS matches read error for %s: %v", detailURL, err)
-        return nil
+        return nil, fmt.Errorf("IS matches fetch error for %s: %w", detailURL, err)
     }
     if os.Getenv("DEBUG_SAVE_HTML") != "" {
         // name the file using the req (competition id) if present
@@ -239,8 +232,7 @@ func parseCompetitionMatchesFromIS(detailURL, clubType, clubName, clubID string)
     }
     docDetail, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
     if err != nil {
-        log.Printf("IS matches parse error for %s: %v", detailURL, err)
-        return nil
+        return nil, fmt.Errorf("IS matches parse error for %s: %w", detailURL, err)
     }
     var matches []Match
     totalRows := 0
@@ -265,6 +257,9 @@ func parseCompetitionMatchesFromIS(detailURL, clubType, clubName, clubID string)
         }
         venue := ""
         if tds.Length() > 4 { venue = getText(tds.Eq(4)) }
+        // Status notes sit in the score cell or in extra columns before the documents
+        marker := rawScore
+        for i := 5; i < tds.Length()-1; i++ { marker += " " + getText(tds.Eq(i)) }
         var reportURL, matchID string
         var isReportHref, isDelegHref string
         // Use the last column for links to be robust to optional columns
@@ -282,13 +277,7 @@ func parseCompetitionMatchesFromIS(detailURL, clubType, clubName, clubID string)
                 isDelegHref = resolveISURL(href)
             }
         })
-        if matchID != "" {
-            if strings.EqualFold(clubType, "futsal") {
-                reportURL = fmt.Sprintf("https://www.fotbal.cz/futsal/zapasy/futsal/%s", matchID)
-            } else {
-                reportURL = fmt.Sprintf("https://www.fotbal.cz/souteze/zapasy/zapas/%s", matchID)
-            }
-        }
+        reportURL = facrMatchURL(clubType, matchID)
         // Canonical fotbal.cz link
         facrLink := reportURL
         // Filter by club involvement: prefer UUID match, fallback to name matching with simplified token
@@ -322,24 +311,15 @@ func parseCompetitionMatchesFromIS(detailURL, clubType, clubName, clubID string)
                 if token != "" && containsFold(rawAway, token) { awayID = clubID }
             }
         }
-        homeLogo := getLogo(rawHome, homeID)
-        awayLogo := getLogo(rawAway, awayID)
-        matches = append(matches, Match{DateTime: dt, Home: rawHome, HomeID: homeID, HomeLogoURL: homeLogo, Away: rawAway, AwayID: awayID, AwayLogoURL: awayLogo, Score: score, Venue: venue, MatchID: matchID, ReportURL: func() string { if isReportHref != "" { return isReportHref }; return reportURL }(), FACRLink: facrLink, DelegationURL: isDelegHref})
+        homeLogo := resolveLogo(ctx, rawHome, homeID)
+        awayLogo := resolveLogo(ctx, rawAway, awayID)
+        matches = append(matches, withStatus(withKickoff(Match{DateTime: dt, Home: rawHome, HomeID: homeID, HomeLogoURL: homeLogo.URL, HomeLogoSource: homeLogo.Source, Away: rawAway, AwayID: awayID, AwayLogoURL: awayLogo.URL, AwayLogoSource: awayLogo.Source, Score: score, Venue: venue, MatchID: matchID, ReportURL: func() string { if isReportHref != "" { return isReportHref }; return reportURL }(), FACRLink: facrLink, DelegationURL: isDelegHref}), marker, time.Now()))
     })
     if os.Getenv("DEBUG_SAVE_HTML") != "" {
         log.Printf("IS parse summary for %s: total rows=%d, kept=%d", detailURL, totalRows, keptRows)
     }
-    return matches
-}
-var logoCache = map[string]string{}
-
-type searchAPIResult struct {
-    Results []struct {
-        Name    string `json:"name"`
-        LogoURL string `json:"logo_url"`
-    } `json:"results"`
+    return matches, nil
 }
-
 // a simplified search token like "krnov" to improve chances of finding a logo.
 func simplifyClubQuery(name string) string {
     s := strings.TrimSpace(name)
@@ -374,93 +354,96 @@ func simplifyClubQuery(name string) string {
     return strings.ToLower(last)
 }
 
-func getLogoBySearch(name string) string {
-    key := strings.ToLower(strings.TrimSpace(name))
-    if key == "" {
-        return ""
-    }
-    if v, ok := logoCache[key]; ok {
-        return v
-    }
-    client := &http.Client{Timeout: 5 * time.Second}
-    // Prefer simplified last-word token (e.g., "krnov") to improve hit rate for logos
-    query := simplifyClubQuery(name)
-    if query == "" {
-        query = name
-    }
-
-    doSearch := func(q string) (searchAPIResult, bool) {
-        url := fmt.Sprintf("http://localhost:8686/club/search?q=%s", neturl.QueryEscape(q))
-        resp, err := client.Get(url)
-        if err != nil {
-            return searchAPIResult{}, false
-        }
-        defer resp.Body.Close()
-        if resp.StatusCode != http.StatusOK {
-            io.Copy(io.Discard, resp.Body)
-            return searchAPIResult{}, false
-        }
-        var payload searchAPIResult
-        if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-            return searchAPIResult{}, false
-        }
-        return payload, true
-    }
-
-    payload, ok := doSearch(query)
-    if !ok || len(payload.Results) == 0 {
-        // Fallback to full name if simplified token yields nothing
-        payload, ok = doSearch(name)
-        if !ok {
-            return ""
-        }
-    }
-    // pick best match: exact (case-insensitive), then contains, else first
-    best := ""
-    for _, r := range payload.Results {
-        if strings.EqualFold(strings.TrimSpace(r.Name), strings.TrimSpace(name)) {
-            best = r.LogoURL
-            break
-        }
-    }
-    if best == "" {
-        for _, r := range payload.Results {
-            rname := strings.ToLower(r.Name)
-            if strings.Contains(rname, key) || strings.Contains(key, rname) {
-                best = r.LogoURL
-                break
-            }
-        }
-    }
-    if best == "" && len(payload.Results) > 0 {
-        best = payload.Results[0].LogoURL
-    }
-    logoCache[key] = best
-    return best
+// CompetitionTable holds the standings sections of an IS table page: the
+// overall table, home and away tables and any further sections in page order.
+// The V2 fields and ParseErrors are filled only when typed rows are requested (?v=2).
+type CompetitionTable struct {
+	Overall     []TableRow        `json:"overall,omitempty"`
+	Home        []TableRow        `json:"home,omitempty"`
+	Away        []TableRow        `json:"away,omitempty"`
+	Other       []TableSection    `json:"other,omitempty"`
+	OverallV2   []TableRowV2      `json:"overall_v2,omitempty"`
+	HomeV2      []TableRowV2      `json:"home_v2,omitempty"`
+	AwayV2      []TableRowV2      `json:"away_v2,omitempty"`
+	ParseErrors []TableParseError `json:"parse_errors,omitempty"`
 }
 
-func getLogo(teamName string, teamID string) string {
-	placeholder := "https://www.fotbal.cz/dist/img/logo-club-empty.svg"
-	name := strings.ToLower(strings.TrimSpace(teamName))
-	if name == "" || strings.Contains(name, "volno") || strings.Contains(name, "volný los") || strings.Contains(name, "volny los") || strings.Contains(name, "bye") {
-		return placeholder
-	}
-	// If we have a team ID, construct the official logo URL directly.
-	// This avoids wrong matches for duplicate names (e.g., multiple "Ořechov").
-	if tid := strings.TrimSpace(teamID); tid != "" {
-		return fmt.Sprintf("https://is1.fotbal.cz/media/kluby/%s/%s_crop.jpg", tid, tid)
-	}
-	// Otherwise, try the local search endpoint by name.
-	if logo := getLogoBySearch(teamName); logo != "" {
-		return logo
+// parseCompetitionTable reads the standings sections selected by sections
+// from an IS tabulky-souteze page. Every section is an h3 heading followed
+// by a .list.tabulky table.
+func parseCompetitionTable(ctx context.Context, docTable *goquery.Document, sections tableSections) *CompetitionTable {
+	parseSection := func(list *goquery.Selection) []TableRow {
+		var rows []TableRow
+		table := list.Find("table.vysledky-tabulky tbody")
+		table.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+			// skip header rows containing th
+			if tr.Find("th").Length() > 0 {
+				return
+			}
+			tds := tr.Find("td")
+			if tds.Length() < 8 {
+				return
+			}
+			get := func(i int) string { return strings.TrimSpace(tds.Eq(i).Text()) }
+			rank := get(0)
+			team := get(1)
+			teamID := extractUUIDFromHref(tds.Eq(1).Find("a").First().AttrOr("href", ""))
+			played := get(2)
+			wins := get(3)
+			draws := get(4)
+			losses := get(5)
+			scoreRaw := get(6)
+			// normalize score like "5 : 0" -> "5:0"
+			score := scoreRaw
+			if re := regexp.MustCompile(`\s*([0-9]+)\s*:\s*([0-9]+)\s*`); re != nil {
+				if m := re.FindStringSubmatch(scoreRaw); len(m) == 3 {
+					score = fmt.Sprintf("%s:%s", m[1], m[2])
+				}
+			}
+			points := get(7)
+			logo := resolveLogo(ctx, team, teamID)
+			rows = append(rows, TableRow{
+				Rank: rank, Team: team, TeamID: teamID, TeamLogoURL: logo.URL, TeamLogoSource: logo.Source, Played: played, Wins: wins, Draws: draws, Losses: losses, Score: score, Points: points,
+			})
+		})
+		return rows
 	}
-	// No ID and no search hit -> placeholder
-	return placeholder
-}
 
-// CompetitionTable holds standings sections; currently only Overall is used
-type CompetitionTable struct {
-	Overall []TableRow `json:"overall"`
+	table := &CompetitionTable{}
+	docTable.Find("h3").Each(func(_ int, h3 *goquery.Selection) {
+		list := h3.NextUntil("h3").Filter("div.list.tabulky").First()
+		title := collapseSpaces(h3.Text())
+		if list.Length() == 0 || title == "" {
+			return
+		}
+		key := tableSectionKey(title)
+		if !sections.wants(key) {
+			return
+		}
+		// Keep the first section of each kind
+		switch key {
+		case "overall":
+			if table.Overall == nil {
+				table.Overall = parseSection(list)
+			}
+		case "home":
+			if table.Home == nil {
+				table.Home = parseSection(list)
+			}
+		case "away":
+			if table.Away == nil {
+				table.Away = parseSection(list)
+			}
+		default:
+			for _, other := range table.Other {
+				if other.Key == key {
+					return
+				}
+			}
+			table.Other = append(table.Other, TableSection{Key: key, Title: title, Rows: parseSection(list)})
+		}
+	})
+	return table
 }
 
 // ClubInfo is the response for club info and tables endpoints
@@ -473,6 +456,7 @@ type ClubInfo struct {
 	LogoURL        string        `json:"logo_url,omitempty"`
 	Address        string        `json:"address,omitempty"`
 	Category       string        `json:"category,omitempty"`
+	Season         string        `json:"season,omitempty"` // set when another than the current season was requested
 	Competitions   []Competition `json:"competitions"`
 }
 
@@ -495,72 +479,56 @@ func getClubSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	results, err := searchClubs(r.Context(), q)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error fetching search page: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"query":   q,
+		"count":   len(results),
+		"results": results,
+	})
+}
+
+// searchClubs runs the fotbal.cz club search. A search page that keeps
+// answering with an error status is treated as no results.
+func searchClubs(ctx context.Context, q string) ([]SearchResult, error) {
 	// Build search URL
 	vals := neturl.Values{}
 	vals.Set("q", q)
-	searchURL := "https://www.fotbal.cz/club/hledej?" + vals.Encode()
+	searchURL := fotbalURL("/club/hledej?%s", vals.Encode())
 
-	req, err := http.NewRequest("GET", searchURL, nil)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Error creating request: %v", err), http.StatusInternalServerError)
-		return
-	}
-	// Set headers to mimic a browser; fotbal.cz may 404 otherwise
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "cs-CZ,cs;q=0.9,en;q=0.8")
-	req.Header.Set("Referer", "https://www.fotbal.cz/club/hledej")
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Error fetching search page: %v", err), http.StatusInternalServerError)
-		return
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
+	doc, err := fetchDocument(ctx, searchURL)
+	var statusErr *upstreamStatusError
+	if errors.As(err, &statusErr) {
 		// Retry once. If query has very short tokens, try quoting the whole query.
-		resp.Body.Close()
 		searchURL2 := searchURL
 		tokens := strings.Fields(q)
 		for _, t := range tokens {
 			if len([]rune(t)) <= 2 {
 				vals2 := neturl.Values{}
 				vals2.Set("q", "\""+q+"\"")
-				searchURL2 = "https://www.fotbal.cz/club/hledej?" + vals2.Encode()
+				searchURL2 = fotbalURL("/club/hledej?%s", vals2.Encode())
 				break
 			}
 		}
-		req2, _ := http.NewRequest("GET", searchURL2, nil)
-		req2.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36")
-		req2.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-		req2.Header.Set("Accept-Language", "en-US,en;q=0.9")
-		resp2, err2 := client.Do(req2)
-		if err2 != nil {
-			http.Error(w, fmt.Sprintf("Error fetching (retry): %v", err2), http.StatusBadGateway)
-			return
-		}
-		defer resp2.Body.Close()
-		if resp2.StatusCode != http.StatusOK {
+		var err2 error
+		doc, err2 = fetchDocument(ctx, searchURL2)
+		if errors.As(err2, &statusErr) {
 			// Treat as no results instead of surfacing error to client
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]any{
-				"query":   q,
-				"count":   0,
-				"results": []SearchResult{},
-			})
-			return
+			return []SearchResult{}, nil
 		}
-		// replace resp with resp2 for downstream parsing
-		resp = resp2
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Error parsing HTML: %v", err), http.StatusInternalServerError)
-		return
+		if err2 != nil {
+			return nil, fmt.Errorf("retry: %w", err2)
+		}
+	} else if err != nil {
+		return nil, err
 	}
 
-	var results []SearchResult
+	results := []SearchResult{}
 	// The page lists clubs in section "Výsledky hledání" as li.ListItemSplit
 	doc.Find("li.ListItemSplit").Each(func(_ int, li *goquery.Selection) {
 		a := li.Find("a.Link--inverted").First()
@@ -597,7 +565,7 @@ func getClubSearch(w http.ResponseWriter, r *http.Request) {
 
 		// Normalize URL (ensure absolute)
 		if !strings.HasPrefix(href, "http://") && !strings.HasPrefix(href, "https://") {
-			href = "https://www.fotbal.cz" + href
+			href = fotbalURL("/%s", strings.TrimPrefix(href, "/"))
 		}
 
 		results = append(results, SearchResult{
@@ -610,13 +578,7 @@ func getClubSearch(w http.ResponseWriter, r *http.Request) {
 			Address:  address,
 		})
 	})
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]any{
-		"query":   q,
-		"count":   len(results),
-		"results": results,
-	})
+	return results, nil
 }
 
 // getClubTables returns club info with competition standings tables (no matches)
@@ -630,174 +592,62 @@ func getClubTables(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate club type
-	var baseURL string
-	var sportParam string
-	switch clubType {
-	case "football":
-		baseURL = "https://www.fotbal.cz/souteze/club/club"
-		sportParam = "fotbal"
-	case "futsal":
-		baseURL = "https://www.fotbal.cz/futsal/club/club"
-		sportParam = "futsal"
-	default:
+	if _, ok := sportParamFor(clubType); !ok {
 		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
 		return
 	}
 
-	url := fmt.Sprintf("%s/%s", baseURL, clubID)
-	resp, err := http.Get(url)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Error fetching club data: %v", err), http.StatusInternalServerError)
+	ctx, ok := seasonContext(w, r)
+	if !ok {
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		http.Error(w, fmt.Sprintf("Error: received status code %d", resp.StatusCode), resp.StatusCode)
+	opts, ok := tableOptionsFromRequest(w, r)
+	if !ok {
 		return
 	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	clubInfo, err := scrapeClubTables(ctx, clubType, clubID, opts)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Error parsing HTML: %v", err), http.StatusInternalServerError)
+		writeFetchError(w, "club data", err)
 		return
 	}
-	// Extract club internal ID
-	clubInternalID := ""
-	doc.Find("section").Each(func(i int, s *goquery.Selection) {
-		headerText := s.Find("h3 span").First().Text()
-		if strings.TrimSpace(headerText) == "ID klubu" {
-			clubInternalID = strings.TrimSpace(s.Find("ul li").First().Text())
-		}
-	})
 
-	// Extract competitions
-	var competitions []Competition
-	doc.Find("table.Table tbody tr").Each(func(i int, s *goquery.Selection) {
-		code := strings.TrimSpace(s.Find("td:first-child").Text())
-		nameLink := s.Find("td:nth-child(2) a")
-		name := strings.TrimSpace(nameLink.Text())
-		teamCount := strings.TrimSpace(s.Find("td:nth-child(3)").Text())
-		// Extract competition ID from the link
-		parts := strings.Split(nameLink.AttrOr("href", ""), "/")
-		compID := ""
-		if len(parts) >= 2 {
-			compID = parts[len(parts)-1]
-		}
-		// Build public table link depending on clubType
-		tableLink := ""
-		if strings.EqualFold(clubType, "futsal") {
-			tableLink = fmt.Sprintf("https://www.fotbal.cz/futsal/futsal/table/%s", compID)
-		} else {
-			tableLink = fmt.Sprintf("https://www.fotbal.cz/souteze/turnaje/table/%s", compID)
-		}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clubInfo)
+}
 
-		competitions = append(competitions, Competition{
-			ID:          compID,
-			Code:        code,
-			Name:        name,
-			TeamCount:   teamCount,
-			MatchesLink: tableLink,
-		})
-	})
+// scrapeClubTables scrapes the club page and the standings of all its
+// competitions.
+func scrapeClubTables(ctx context.Context, clubType, clubID string, opts tableOptions) (*ClubInfo, error) {
+	club, err := scrapeClubPage(ctx, clubType, clubID)
+	if err != nil {
+		return nil, err
+	}
+	sportParam, _ := sportParamFor(clubType)
+	competitions := club.Competitions
 
 	// For each competition, fetch the standings tables from is.fotbal.cz
-	for i := range competitions {
+	forEachLimited(len(competitions), scrapeConcurrency, func(i int) {
 		comp := &competitions[i]
-		tableURL := fmt.Sprintf("https://is.fotbal.cz/public/souteze/tabulky-souteze.aspx?req=%s&sport=%s", comp.ID, sportParam)
-		resp, err := http.Get(tableURL)
+		docTable, err := fetchDocument(ctx, isCompetitionTableURL(comp.ID, sportParam))
 		if err != nil {
 			log.Printf("error fetching competition table for %s: %v", comp.ID, err)
-			continue
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			log.Printf("non-200 response for %s: %d", comp.ID, resp.StatusCode)
-			continue
+			comp.Error = err.Error()
+			return
 		}
-
-		docTable, err := goquery.NewDocumentFromReader(resp.Body)
-		if err != nil {
-			log.Printf("error parsing table HTML for %s: %v", comp.ID, err)
-			continue
+		comp.Table = parseCompetitionTable(ctx, docTable, opts.Sections)
+		if opts.Form > 0 {
+			matches, err := competitionMatches(ctx, *comp, clubType, sportParam, "", "")
+			if err != nil {
+				log.Printf("error fetching matches for form of %s: %v", comp.ID, err)
+			}
+			withForm(comp.Table, matches, opts.Form, time.Now())
 		}
-
-		// Parse section: Tabulka celková (only overall)
-		var overall []TableRow
-
-		parseSection := func(headerText string) []TableRow {
-			var rows []TableRow
-			// Find the h3 with matching text, then the following .list.tabulky table
-			docTable.Find("h3").EachWithBreak(func(_ int, h3 *goquery.Selection) bool {
-				if strings.EqualFold(strings.TrimSpace(h3.Text()), headerText) {
-					list := h3.NextAllFiltered("div.list.tabulky").First()
-					if list.Length() == 0 {
-						return false
-					}
-					table := list.Find("table.vysledky-tabulky tbody")
-					table.Find("tr").Each(func(_ int, tr *goquery.Selection) {
-						// skip header rows containing th
-						if tr.Find("th").Length() > 0 {
-							return
-						}
-						tds := tr.Find("td")
-						if tds.Length() < 8 {
-							return
-						}
-						get := func(i int) string { return strings.TrimSpace(tds.Eq(i).Text()) }
-						rank := get(0)
-						team := get(1)
-						teamID := extractUUIDFromHref(tds.Eq(1).Find("a").First().AttrOr("href", ""))
-						played := get(2)
-						wins := get(3)
-						draws := get(4)
-						losses := get(5)
-						scoreRaw := get(6)
-						// normalize score like "5 : 0" -> "5:0"
-						score := scoreRaw
-						if re := regexp.MustCompile(`\s*([0-9]+)\s*:\s*([0-9]+)\s*`); re != nil {
-							if m := re.FindStringSubmatch(scoreRaw); len(m) == 3 {
-								score = fmt.Sprintf("%s:%s", m[1], m[2])
-							}
-						}
-						points := get(7)
-						rows = append(rows, TableRow{
-							Rank: rank, Team: team, TeamID: teamID, TeamLogoURL: getLogo(team, teamID), Played: played, Wins: wins, Draws: draws, Losses: losses, Score: score, Points: points,
-						})
-					})
-					return false
-				}
-				return true
-			})
-			return rows
+		if opts.V2 {
+			withTableV2(comp.Table)
 		}
+	})
 
-		overall = parseSection("Tabulka celková")
-		comp.Table = &CompetitionTable{Overall: overall}
-	}
-
-	clubName := strings.TrimSpace(doc.Find("h1.H4 span").First().Text())
-	clubURL := strings.TrimSpace(doc.Find("h1.H4 a").First().AttrOr("href", ""))
-	logoURL := strings.TrimSpace(doc.Find("img.Logo").First().AttrOr("src", ""))
-	category := strings.TrimSpace(doc.Find("section").First().Find("h3 span").First().Text())
-	address := strings.TrimSpace(doc.Find("section").First().Find("ul li").First().Text())
-
-	clubInfo := ClubInfo{
-		Name:           clubName,
-		ClubID:         clubID,
-		ClubType:       clubType,
-		ClubInternalID: clubInternalID,
-		URL:            clubURL,
-		LogoURL:        logoURL,
-		Address:        address,
-		Category:       category,
-		Competitions:   competitions,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(clubInfo)
+	return club, nil
 }
 
 // getClubInfo returns club info with competitions and matches
@@ -809,40 +659,102 @@ func getClubInfo(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Club ID is required", http.StatusBadRequest)
 		return
 	}
-	var baseURL, sportParam string
-	switch clubType {
-	case "football":
-		baseURL = "https://www.fotbal.cz/souteze/club/club"
-		sportParam = "fotbal"
-	case "futsal":
-		baseURL = "https://www.fotbal.cz/futsal/club/club"
-		sportParam = "futsal"
-	default:
+	if _, ok := sportParamFor(clubType); !ok {
 		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
 		return
 	}
 
-	url := fmt.Sprintf("%s/%s", baseURL, clubID)
-	resp, err := http.Get(url)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Error fetching club data: %v", err), http.StatusInternalServerError)
+	ctx, ok := seasonContext(w, r)
+	if !ok {
 		return
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		http.Error(w, fmt.Sprintf("Error: received status code %d", resp.StatusCode), resp.StatusCode)
+	clubInfo, err := scrapeClubInfo(ctx, clubType, clubID, queryFlag(r, "officials"))
+	if err != nil {
+		writeFetchError(w, "club data", err)
 		return
 	}
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clubInfo)
+}
+
+// scrapeClubInfo scrapes the club page and the matches of all its competitions.
+// withOfficials also embeds the delegation report of every IS match.
+func scrapeClubInfo(ctx context.Context, clubType, clubID string, withOfficials bool) (*ClubInfo, error) {
+	club, err := scrapeClubPage(ctx, clubType, clubID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Error parsing HTML: %v", err), http.StatusInternalServerError)
-		return
+		return nil, err
+	}
+	sportParam, _ := sportParamFor(clubType)
+	clubName := club.Name
+	competitions := club.Competitions
+
+	// Fetch matches of all competitions concurrently; a failing competition
+	// keeps its error and does not fail the whole club
+	forEachLimited(len(competitions), scrapeConcurrency, func(i int) {
+		comp := &competitions[i]
+		matches, err := competitionMatches(ctx, *comp, clubType, sportParam, clubName, clubID)
+		if err != nil {
+			log.Printf("error fetching matches for %s: %v", comp.ID, err)
+			comp.Error = err.Error()
+			return
+		}
+		comp.Matches = matches
+	})
+
+	// Optionally embed referees and officials from the IS delegation reports
+	if withOfficials {
+		var pending []*Match
+		var comps []*Competition
+		for i := range competitions {
+			for j := range competitions[i].Matches {
+				if m := &competitions[i].Matches[j]; m.MatchID != "" && m.DelegationURL != "" {
+					pending = append(pending, m)
+					comps = append(comps, &competitions[i])
+				}
+			}
+		}
+		forEachLimited(len(pending), scrapeConcurrency, func(i int) {
+			m := pending[i]
+			delegation, err := fetchMatchDelegation(ctx, m.MatchID)
+			if err != nil {
+				log.Printf("delegation fetch error for %s: %v", m.MatchID, err)
+				return
+			}
+			m.Officials = delegation
+			referees.add(clubType, watchedMatch{Match: *m, CompetitionID: comps[i].ID, CompetitionName: comps[i].Name}, delegation)
+		})
+		if len(pending) > 0 {
+			referees.save()
+		}
+	}
+
+	return club, nil
+}
+
+// scrapeClubPage reads club metadata and the competition list (without
+// matches) from the fotbal.cz club page.
+func scrapeClubPage(ctx context.Context, clubType, clubID string) (*ClubInfo, error) {
+	var baseURL string
+	switch clubType {
+	case "football":
+		baseURL = fotbalURL("/souteze/club/club")
+	case "futsal":
+		baseURL = fotbalURL("/futsal/club/club")
+	default:
+		return nil, fmt.Errorf("invalid club type %q", clubType)
+	}
+
+	url := fmt.Sprintf("%s/%s", baseURL, clubID)
+	doc, err := fetchDocument(ctx, url)
+	if err != nil {
+		return nil, err
 	}
 
 	clubName := strings.TrimSpace(doc.Find("h1.H4 span").First().Text())
 	// Basic club metadata
 	clubURL := fmt.Sprintf("%s/%s", baseURL, clubID)
-	logoURL := fmt.Sprintf("https://is1.fotbal.cz/media/kluby/%s/%s_crop.jpg", clubID, clubID)
+	logoURL := clubLogoURL(clubID)
 	category := "Fotbal"
 	if strings.EqualFold(clubType, "futsal") {
 		category = "Futsal"
@@ -870,32 +782,18 @@ func getClubInfo(w http.ResponseWriter, r *http.Request) {
 			compID = parts[len(parts)-1]
 		}
 		// Public table URL for convenience
-		tableLink := ""
-		if strings.EqualFold(clubType, "futsal") {
-			tableLink = fmt.Sprintf("https://www.fotbal.cz/futsal/futsal/table/%s", compID)
-		} else {
-			tableLink = fmt.Sprintf("https://www.fotbal.cz/souteze/turnaje/table/%s", compID)
-		}
+		tableLink := fotbalCompetitionURL(clubType, compID)
 		competitions = append(competitions, Competition{ID: compID, Code: code, Name: name, TeamCount: teamCount, MatchesLink: tableLink})
 	})
-
-	// For each competition, fetch matches
-	for i := range competitions {
-		comp := &competitions[i]
-		matchesLink := comp.MatchesLink
-		// 1) Try parsing from the public fotbal.cz competition page (matches_link)
-		matches := parseCompetitionMatchesFromFotbal(matchesLink, clubType, clubName, clubID)
-        // Always try IS as well and prefer it if it provides at least as many matches
-        detailURL := fmt.Sprintf("https://is.fotbal.cz/public/souteze/detail-souteze.aspx?req=%s&sport=%s", comp.ID, sportParam)
-        isMatches := parseCompetitionMatchesFromIS(detailURL, clubType, clubName, clubID)
-        // Prefer IS whenever it yields any results, as IS often contains alias team names
-        if len(isMatches) > 0 {
-            matches = isMatches
-        }
-        comp.Matches = matches
+	// The club page only lists the current season; other seasons come from IS
+	season := seasonFrom(ctx)
+	if season != "" {
+		if competitions, err = seasonCompetitions(ctx, clubType, clubID, season); err != nil {
+			return nil, err
+		}
 	}
 
-	clubInfo := ClubInfo{
+	return &ClubInfo{
 		Name:           clubName,
 		ClubID:         clubID,
 		ClubType:       clubType,
@@ -904,26 +802,117 @@ func getClubInfo(w http.ResponseWriter, r *http.Request) {
 		LogoURL:        logoURL,
 		Address:        address,
 		Category:       category,
+		Season:         season,
 		Competitions:   competitions,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(clubInfo)
+	}, nil
 }
 
 func main() {
+    addr := flag.String("addr", ":8686", "listen address")
+    configPath := flag.String("config", "", "JSON configuration file (upstream origins, clubs to export)")
+    var flagUpstreams Upstreams
+    flag.StringVar(&flagUpstreams.Fotbal, "fotbal-url", "", "www.fotbal.cz origin (env FOTBAL_BASE_URL)")
+    flag.StringVar(&flagUpstreams.IS, "is-url", "", "is.fotbal.cz origin (env IS_BASE_URL)")
+    flag.StringVar(&flagUpstreams.Media, "media-url", "", "is1.fotbal.cz origin (env MEDIA_BASE_URL)")
+    fixturesDir := flag.String("fixtures", "", "serve upstream pages from this fixture directory instead of the internet")
+    recordDir := flag.String("record", "", "save every fetched upstream page into this fixture directory")
+    refereesPath := flag.String("referees", os.Getenv("REFEREES_FILE"), "file keeping the referee index (env REFEREES_FILE; empty rebuilds it on demand after a restart)")
+    webhooksPath := flag.String("webhooks", os.Getenv("WEBHOOKS_FILE"), "file keeping registered webhooks and their match snapshots (env WEBHOOKS_FILE; empty keeps them in memory)")
+    flag.DurationVar(&streamInterval, "stream-interval", streamInterval, "how often streamed clubs and competitions are polled (env STREAM_INTERVAL)")
+    webhookInterval := flag.Duration("webhook-interval", envDuration("WEBHOOK_INTERVAL", 10*time.Minute), "how often watched clubs and competitions are polled (env WEBHOOK_INTERVAL)")
+    flag.StringVar(&adminToken, "admin-token", os.Getenv("ADMIN_TOKEN"), "bearer token required by the webhook endpoints (env ADMIN_TOKEN; empty disables them)")
+    flag.Usage = usage
+    flag.Parse()
+    if *webhookInterval <= 0 {
+        log.Fatalf("webhook interval must be positive, got %s", *webhookInterval)
+    }
+    if streamInterval <= 0 {
+        log.Fatalf("stream interval must be positive, got %s", streamInterval)
+    }
+
+    cfg, err := loadConfig(*configPath)
+    if err != nil {
+        log.Fatalf("config: %v", err)
+    }
+    // Later sources win: defaults, config file, environment, flags
+    upstream = defaultUpstreams().withOverrides(cfg.Upstreams).withOverrides(upstreamsFromEnv()).withOverrides(flagUpstreams)
+
+    responseCache = newCacheStoreFromEnv()
+    if *fixturesDir != "" {
+        srv, err := newFixtureServer(*fixturesDir)
+        if err != nil {
+            log.Fatalf("fixtures: %v", err)
+        }
+        defer srv.Close()
+        upstream = fixtureUpstreams(srv.URL)
+        log.Printf("serving upstream pages from %s at %s", *fixturesDir, srv.URL)
+        // Fixtures are local and fixed: no politeness delay, no persistent cache
+        opts := fetcherOptionsFromEnv()
+        opts.HostDelay = 0
+        fetcher = newHTTPFetcher(opts)
+        responseCache = newMemoryStore(2000)
+    }
+    if *recordDir != "" {
+        fetcher = &recordingFetcher{next: fetcher, dir: *recordDir}
+    }
+    fetcher = newCachingFetcher(fetcher, responseCache, activeTTLs)
+
+    // One-shot commands print their result and exit; "serve" or no command runs the API
+    if args := flag.Args(); len(args) > 0 && args[0] != "serve" {
+        exitOnError(runCommand(context.Background(), cfg, args[0], args[1:], os.Stdout))
+        return
+    }
+
+    if *webhooksPath != "" {
+        if err := webhooks.load(*webhooksPath); err != nil {
+            log.Fatalf("webhooks: %v", err)
+        }
+    }
+    if *refereesPath != "" {
+        if err := referees.load(*refereesPath); err != nil {
+            log.Fatalf("referees: %v", err)
+        }
+    }
+    go webhooks.run(context.Background(), *webhookInterval)
+    configClubs = cfg.Clubs
+
+    r := newRouter()
+    fmt.Printf("Server running on http://localhost%s\n", *addr)
+    log.Fatal(http.ListenAndServe(*addr, r))
+}
+
+// newRouter registers every API endpoint.
+func newRouter() *mux.Router {
     r := mux.NewRouter()
+    r.Use(cacheHeadersMiddleware)
     r.HandleFunc("/club/{type}/{id}", getClubInfo).Methods("GET")
     r.HandleFunc("/club/{type}/{id}/table", getClubTables).Methods("GET")
+    r.HandleFunc("/club/{type}/{id}/calendar.ics", getClubCalendar).Methods("GET")
+    r.HandleFunc("/club/{type}/{id}/next", getClubNext).Methods("GET")
+    r.HandleFunc("/club/{type}/{id}/last", getClubLast).Methods("GET")
+    r.HandleFunc("/club/{type}/{id}/players", getClubPlayers).Methods("GET")
+    r.HandleFunc("/club/{type}/{id}/stats/players", getClubPlayerStats).Methods("GET")
+    r.HandleFunc("/club/{type}/{id}/referees", getClubReferees).Methods("GET")
     r.HandleFunc("/club/search", getClubSearch).Methods("GET")
+    r.HandleFunc("/match/{type}/{matchID}/report", getMatchReport).Methods("GET")
+    r.HandleFunc("/match/{type}/{matchID}/delegation", getMatchDelegation).Methods("GET")
+    r.HandleFunc("/competition/{type}/{competitionID}", getCompetition).Methods("GET")
+    r.HandleFunc("/competition/{type}/{competitionID}/scorers", getCompetitionScorers).Methods("GET")
+    r.HandleFunc("/h2h/{type}/{clubA}/{clubB}", getHeadToHead).Methods("GET")
+    r.HandleFunc("/referees/{query}", getReferee).Methods("GET")
+    r.HandleFunc("/venues", getVenues).Methods("GET")
+    r.HandleFunc("/venues/{id}/matches", getVenueMatches).Methods("GET")
+    r.HandleFunc("/club/{type}/{id}/stream", getClubStream).Methods("GET")
+    r.HandleFunc("/competition/{type}/{competitionID}/stream", getCompetitionStream).Methods("GET")
+    r.HandleFunc("/webhooks", postWebhook).Methods("POST")
+    r.HandleFunc("/webhooks", getWebhooks).Methods("GET")
+    r.HandleFunc("/webhooks/{id}", deleteWebhook).Methods("DELETE")
     r.HandleFunc("/club/{id:[0-9a-fA-F-]+}", func(w http.ResponseWriter, r *http.Request) {
         vars := mux.Vars(r)
         http.Redirect(w, r, "/club/football/"+vars["id"], http.StatusMovedPermanently)
     }).Methods("GET")
     r.HandleFunc("/", docsHandler)
-    port := ":8686"
-    fmt.Printf("Server running on http://localhost%s\n", port)
-    log.Fatal(http.ListenAndServe(port, r))
+    return r
 }
 
 // docsHandler serves a simple HTML API documentation at the root endpoint.
@@ -998,6 +987,10 @@ func docsHandler(w http.ResponseWriter, r *http.Request) {
     <ul>
       <li><code>{type}</code>: <code>football</code> | <code>futsal</code></li>
       <li><code>{id}</code>: club UUID from fotbal.cz</li>
+      <li><code>date_time</code> keeps the scraped text; <code>kickoff</code> / <code>kickoff_utc</code> are RFC 3339 times (Europe/Prague / UTC). <code>date_only</code> is set when the kickoff time is not published yet; <code>kickoff</code> / <code>kickoff_utc</code> are then omitted.</li>
+      <li><code>status</code>: <code>scheduled</code> | <code>played</code> | <code>postponed</code> | <code>cancelled</code> | <code>forfeited</code> | <code>bye</code>. Upstream prints <code>0:0</code> for unplayed matches too, so only <code>played</code> and <code>forfeited</code> scores are results. <code>note</code> carries the upstream text of a postponement, cancellation or forfeit (<code>kontumace</code>) and <code>volný los</code> for byes.</li>
+      <li><code>?officials=1</code>: embed referees and officials of each match as <code>officials</code> (one extra request per match)</li>
+      <li><code>?season=2024/2025</code>: another season. fotbal.cz lists only the current competitions, so the club's competitions of that season (their IDs change every season) are discovered on the IS club page; the response has the same shape plus <code>season</code>, and 404 when IS has nothing for the season.</li>
     </ul>
     <p>Example: <a id="ex-info" href="/club/football/00000000-0000-0000-0000-000000000000">/club/football/{id}</a></p>
     <details>
@@ -1021,13 +1014,18 @@ func docsHandler(w http.ResponseWriter, r *http.Request) {
       "matches": [
         {
           "date_time": "12.08.2023 18:00",
+          "kickoff": "2023-08-12T18:00:00+02:00",
+          "kickoff_utc": "2023-08-12T16:00:00Z",
           "home": "AC Sparta Praha",
           "home_id": "00000000-0000-0000-0000-000000000000",
           "home_logo_url": "https://.../sparta.png",
+          "home_logo_source": "team_id",
           "away": "SK Slavia Praha",
           "away_id": "11111111-1111-1111-1111-111111111111",
           "away_logo_url": "https://.../slavia.png",
+          "away_logo_source": "search_exact",
           "score": "2:1",
+          "status": "played",
           "venue": "Stadion Letná",
           "match_id": "match12345",
           "report_url": "https://www.fotbal.cz/..."
@@ -1043,6 +1041,10 @@ func docsHandler(w http.ResponseWriter, r *http.Request) {
     <h2>Club Tables (Standings)</h2>
     <p><strong>GET</strong> <code>/club/{type}/{id}/table</code></p>
     <p>Returns standings (overall table) for each competition of the club.</p>
+    <p>Add <code>?v=2</code> for typed rows in <code>overall_v2</code> (integers, <code>goals_for</code>, <code>goals_against</code>, <code>goal_difference</code>). Cells that are not numbers are listed in <code>parse_errors</code>.</p>
+    <p>Besides <code>overall</code>, tables carry the <code>home</code> and <code>away</code> sections and any further IS sections under <code>other</code> (<code>key</code>, <code>title</code>, <code>rows</code>). Pick sections with <code>?sections=overall,home</code>; <code>other</code> selects all further sections, a section <code>key</code> just one. Sections left out are omitted from the JSON.</p>
+    <p><code>?season=2024/2025</code> returns the standings of another season, discovered like for the club info endpoint.</p>
+    <p><code>?include=form</code> adds the last results of every team (<code>form_count</code>, default 5, at most 20; anything but a positive number is rejected with <code>400</code>) as <code>form</code>: the string (<code>"WWDLW"</code>, newest first), the current <code>streak</code> (<code>"W2"</code>) and the <code>results</code> with opponent, score and date. Home and away sections count only home and away matches.</p>
     <p>Example: <a id="ex-table" href="/club/football/00000000-0000-0000-0000-000000000000/table">/club/football/{id}/table</a></p>
     <details>
       <summary>Response shape</summary>
@@ -1067,6 +1069,7 @@ func docsHandler(w http.ResponseWriter, r *http.Request) {
             "team": "AC Sparta Praha",
             "team_id": "00000000-0000-0000-0000-000000000000",
             "team_logo_url": "https://.../sparta.png",
+            "team_logo_source": "team_id",
             "played": "10",
             "wins": "8",
             "draws": "2",
@@ -1094,6 +1097,254 @@ func docsHandler(w http.ResponseWriter, r *http.Request) {
     </details>
   </section>
 
+  <section class="ep">
+    <h2>Club Calendar (iCalendar)</h2>
+    <p><strong>GET</strong> <code>/club/{type}/{id}/calendar.ics</code></p>
+    <p>All fixtures of the club as an RFC 5545 calendar, one event per match. Subscribe to the URL in a phone or desktop calendar; event UIDs are based on the match ID, so rescheduled matches move instead of being duplicated. Played matches show the score in the description.</p>
+  </section>
+
+  <section class="ep">
+    <h2>Next Match / Last Result</h2>
+    <p><strong>GET</strong> <code>/club/{type}/{id}/next</code> and <code>/club/{type}/{id}/last</code></p>
+    <p>Compact payload for website widgets: the club's upcoming fixtures (soonest first) or played matches (newest first) across all competitions, with both team logos. <code>?count=N</code> returns more than one match (max 20); <code>?competition=</code> takes competition IDs or codes, comma separated, and only those competitions are scraped.</p>
+    <details>
+      <summary>Response shape</summary>
+      <pre>{
+  "club_id": "441d3783-06aa-436a-b438-359300ee0371",
+  "club_type": "futsal",
+  "name": "FC Bizoni Uherské Hradiště, z.s.",
+  "logo_url": "https://is1.fotbal.cz/media/kluby/.../..._crop.jpg",
+  "matches": [ {
+    "competition": "2. Futsal liga - východ",
+    "competition_code": "O2V",
+    "competition_id": "f49e63bd-55d9-4c5e-93f7-8e482262b88f",
+    "date_time": "23.10.2026 19:00",
+    "kickoff": "2026-10-23T19:00:00+02:00",
+    "home": "FC Tango Hodonín",
+    "home_logo_url": "...",
+    "away": "FC Bizoni Uherské Hradiště, z.s.",
+    "away_logo_url": "...",
+    "is_home": false,
+    "venue": "SH Hodonín",
+    "match_id": "..."
+  } ]
+}</pre>
+    </details>
+  </section>
+
+  <section class="ep">
+    <h2>Club Players (Roster)</h2>
+    <p><strong>GET</strong> <code>/club/{type}/{id}/players</code></p>
+    <p>The club's squad per competition, aggregated from the IS match reports of its played matches: shirt <code>numbers</code> used, <code>squad</code> (reports listing the player), <code>appearances</code> and <code>starts</code>. In football a substitute appears only when a substitution brought them on; futsal substitutions are rolling, so every listed player counts. Forfeited matches have no lineups and are skipped. <code>?competition=</code> takes competition IDs or codes (comma separated) and only scrapes those. Reports of finished matches are cached (<code>CACHE_TTL_FINISHED_REPORT</code>), so the first call is the slow one.</p>
+    <details>
+      <summary>Response shape</summary>
+      <pre>{
+  "club_id": "441d3783-06aa-436a-b438-359300ee0371",
+  "club_type": "futsal",
+  "name": "FC Bizoni Uherské Hradiště, z.s.",
+  "teams": [ {
+    "competition_id": "f49e63bd-55d9-4c5e-93f7-8e482262b88f",
+    "competition_code": "O2V",
+    "competition_name": "2. Futsal liga - východ",
+    "team": "FC Bizoni Uherské Hradiště, z.s.",
+    "reports": 3,
+    "players": [ { "name": "Jan Novák", "numbers": ["7"], "squad": 3, "appearances": 3, "starts": 3 } ]
+  } ]
+}</pre>
+    </details>
+  </section>
+
+  <section class="ep">
+    <h2>Player Statistics</h2>
+    <p><strong>GET</strong> <code>/club/{type}/{id}/stats/players</code> · <strong>GET</strong> <code>/competition/{type}/{competitionID}/scorers</code></p>
+    <p>Season statistics per player from the IS match reports of played matches: <code>appearances</code>, <code>starts</code>, <code>goals</code> (of which <code>penalty_goals</code>), <code>own_goals</code>, <code>yellow_cards</code> and <code>red_cards</code>. In football <code>minutes</code> are derived from substitutions and red cards (stoppage time is not counted); futsal substitutions are rolling and not recorded, so minutes are omitted and every listed player counts as an appearance. The club endpoint sums all competitions of the club and takes <code>?competition=</code> like the players endpoint. The scorers endpoint covers every team of a competition, sorted by goals, and lists only players who scored unless <code>?all=1</code> is given. Reports of finished matches are parsed once and cached (<code>CACHE_TTL_FINISHED_REPORT</code>).</p>
+    <details>
+      <summary>Response shape</summary>
+      <pre>{
+  "id": "f49e63bd-55d9-4c5e-93f7-8e482262b88f",
+  "code": "O2V",
+  "name": "2. Futsal liga - východ",
+  "reports": 9,
+  "type": "futsal",
+  "season": "2026/2027",
+  "players": [ {
+    "name": "Filip Šimek", "team": "FC Baník Ostrava", "team_id": "649dcca8-5574-4ce3-bd4a-183364c80c4f",
+    "numbers": ["7"], "appearances": 3, "starts": 3, "goals": 5, "yellow_cards": 0, "red_cards": 0
+  } ]
+}</pre>
+    </details>
+  </section>
+
+  <section class="ep">
+    <h2>Referees</h2>
+    <p><strong>GET</strong> <code>/club/{type}/{id}/referees</code> · <strong>GET</strong> <code>/referees/{name-or-id}</code></p>
+    <p>Referees, assistants, fourth officials and VAR from the IS delegation reports. The club endpoint reads the delegations of all the club's matches (<code>?competition=</code> filters like the players endpoint) and lists every referee the club had with played <code>matches</code> per competition, <code>upcoming</code> delegations and each assignment. Every delegation report the server reads (this endpoint and <code>/club/{type}/{id}?officials=1</code>) goes into a referee index that <code>/referees/{name-or-id}</code> searches by IS person ID, full name or part of a name. Before searching, the lookup indexes the competitions of the clubs in the config file, or with <code>?type=futsal&amp;competition=ID,...</code> those competitions instead; a competition is indexed again only once the fixtures cache TTL has passed, played matches already indexed are not fetched again, and <code>indexed_matches</code> tells how many matches the index knows. The index survives restarts when <code>-referees</code> (env <code>REFEREES_FILE</code>) names a file; otherwise it starts empty and is rebuilt on demand. <code>cards</code> count the cards of matches the official led as referee, from match reports that have been parsed; <code>?cards=1</code> fetches the missing reports.</p>
+    <details>
+      <summary>Response shape</summary>
+      <pre>{
+  "query": "sudi",
+  "indexed_matches": 4,
+  "referees": [ {
+    "id": "87000001",
+    "name": "Petr Sudí",
+    "matches": 4,
+    "upcoming": 0,
+    "cards": { "reports": 4, "yellow": 8, "red": 0 },
+    "competitions": [ { "id": "f49e63bd-55d9-4c5e-93f7-8e482262b88f", "name": "2. Futsal liga - východ", "matches": 3, "cards": { "reports": 3, "yellow": 6, "red": 0 } } ],
+    "assignments": [ {
+      "type": "futsal", "competition_id": "f49e63bd-55d9-4c5e-93f7-8e482262b88f", "competition_name": "2. Futsal liga - východ",
+      "match_id": "d37d7260-f485-2967-674a-b7e568b708ea", "date_time": "18.09.2026 19:00", "kickoff": "2026-09-18T19:00:00+02:00",
+      "home": "FC Bizoni Uherské Hradiště, z.s.", "away": "Futsal klub Havlíčkův Brod, z.s.", "score": "4:2", "status": "played",
+      "role": "referee", "cards": { "reports": 1, "yellow": 2, "red": 0 }
+    } ]
+  } ]
+}</pre>
+    </details>
+  </section>
+
+  <section class="ep">
+    <h2>Venues</h2>
+    <p><strong>GET</strong> <code>/venues</code> · <strong>GET</strong> <code>/venues/{id}/matches</code></p>
+    <p>A venue directory built from every fixture of the competitions of the clubs listed in the <code>-config</code> file (<code>"clubs": [{"type": ..., "id": ...}]</code>, the clubs of the export command); <code>?club=futsal:ID</code> adds up to 10 clubs. A scan of the same clubs is reused by both endpoints for the fixtures cache TTL. Free-text venues are normalised into IDs such as <code>sh-uherske-hradiste</code>: case, diacritics and punctuation are ignored and common words are abbreviated (<i>Sportovní hala</i> = <i>SH</i>, <i>Základní škola</i> = <i>ZŠ</i>), other spellings are kept in <code>aliases</code>. Each venue lists the home <code>clubs</code> playing there. The matches endpoint returns every known fixture at a venue across all scanned competitions, oldest first; <code>?upcoming=1</code> keeps only matches not played yet.</p>
+    <details>
+      <summary>Response shape</summary>
+      <pre>{
+  "id": "sh-uherske-hradiste",
+  "name": "SH Uherské Hradiště",
+  "matches": 4,
+  "upcoming": 1,
+  "clubs": [ { "type": "futsal", "id": "441d3783-06aa-436a-b438-359300ee0371", "name": "FC Bizoni Uherské Hradiště, z.s.", "matches": 4 } ],
+  "fixtures": [ {
+    "date_time": "30.10.2026 19:00", "kickoff": "2026-10-30T19:00:00+01:00",
+    "home": "FC Bizoni Uherské Hradiště, z.s.", "away": "Real Top Frýdek-Místek z.s.",
+    "status": "scheduled", "venue": "SH Uherské Hradiště", "match_id": "...",
+    "competition_id": "f49e63bd-55d9-4c5e-93f7-8e482262b88f", "competition_name": "2. Futsal liga - východ",
+    "type": "futsal"
+  } ]
+}</pre>
+    </details>
+  </section>
+
+  <section class="ep">
+    <h2>Competition (all matches + table)</h2>
+    <p><strong>GET</strong> <code>/competition/{type}/{competitionID}</code></p>
+    <p>Returns competition metadata, every fixture of the competition (not filtered to a club) and the overall table. <code>{competitionID}</code> is the <code>id</code> of a competition from the club endpoints. Supports <code>?v=2</code> and <code>?sections=</code> like the table endpoint. <code>?season=2024/2025</code> follows the season switch of the IS competition page to the same competition in that season (404 when it has none).</p>
+    <details>
+      <summary>Response shape</summary>
+      <pre>{
+  "id": "f49e63bd-55d9-4c5e-93f7-8e482262b88f",
+  "code": "O2V",
+  "name": "2. Futsal liga - východ",
+  "team_count": "12",
+  "matches_link": "https://www.fotbal.cz/futsal/futsal/table/...",
+  "type": "futsal",
+  "season": "2025/2026",
+  "table_url": "https://is.fotbal.cz/public/souteze/tabulky-souteze.aspx?req=...",
+  "matches": [ { "date_time": "19.09.2025 20:15", "home": "AC Hlinsko", "away": "...", "score": "0:0", "...": "..." } ],
+  "table": { "overall": [ { "rank": "1", "team": "...", "points": "26", "...": "..." } ] }
+}</pre>
+    </details>
+  </section>
+
+  <section class="ep">
+    <h2>Head to Head</h2>
+    <p><strong>GET</strong> <code>/h2h/{type}/{clubA}/{clubB}</code></p>
+    <p>All matches between two clubs in the competitions both of them play in, matched by the club UUIDs of the team links (<code>home_id</code>/<code>away_id</code>), oldest first; each shared competition is scraped once. The summary counts played matches from the point of view of <code>clubA</code>; unplayed fixtures are counted as <code>upcoming</code>. A shared competition that could not be scraped is listed with an <code>error</code>.</p>
+    <details>
+      <summary>Response shape</summary>
+      <pre>{
+  "type": "futsal",
+  "club_a": { "id": "441d3783-...", "name": "FC Bizoni", "logo_url": "..." },
+  "club_b": { "id": "202216d4-...", "name": "Real Top", "logo_url": "..." },
+  "summary": { "played": 2, "wins_a": 1, "draws": 1, "wins_b": 0, "goals_a": 5, "goals_b": 3, "upcoming": 1 },
+  "matches": [ { "competition_id": "...", "competition_name": "...", "played": true, "date_time": "...", "home": "...", "away": "...", "score": "3:1", "...": "..." } ],
+  "competitions": [ { "id": "...", "code": "O2V", "name": "2. Futsal liga - východ" } ]
+}</pre>
+    </details>
+  </section>
+
+  <section class="ep">
+    <h2>Live Stream (Server-Sent Events)</h2>
+    <p><strong>GET</strong> <code>/club/{type}/{id}/stream</code> · <strong>GET</strong> <code>/competition/{type}/{competitionID}/stream</code></p>
+    <p>Keeps the connection open and pushes <code>text/event-stream</code> events for match-day screens. All subscribers of the same club or competition share one poller, which re-scrapes it every <code>-stream-interval</code> (env <code>STREAM_INTERVAL</code>, default 1m) bypassing the page cache and stops when the last subscriber disconnects. At most <code>STREAM_MAX_POLLERS</code> (default 20) clubs and competitions are streamed at once; a new one beyond that is refused with <code>503</code> and <code>Retry-After</code>.</p>
+    <ul>
+      <li><code>snapshot</code>: <code>{"watch": {...}, "matches": [...]}</code>, all matches with <code>competition_id</code>, sent once the poller has data</li>
+      <li><code>match</code>: a match whose <code>score</code> or <code>status</code> changed, shaped like a webhook delivery (<code>changes</code>, <code>match</code>, <code>previous</code>)</li>
+      <li><code>error</code>: <code>{"error": "..."}</code> when a poll failed; the stream stays open</li>
+    </ul>
+    <p>Example: <code>new EventSource("/club/futsal/{id}/stream").addEventListener("match", e =&gt; ...)</code></p>
+  </section>
+
+  <section class="ep">
+    <h2>Webhooks</h2>
+    <p><strong>POST</strong> <code>/webhooks</code> · <strong>GET</strong> <code>/webhooks</code> · <strong>DELETE</strong> <code>/webhooks/{id}</code></p>
+    <p>Registers a URL that gets an HTTP POST whenever a match of a club or competition is <code>added</code>, <code>rescheduled</code>, gets a <code>score</code>, changes <code>venue</code> or <code>status</code>. A background poller re-scrapes every watched club or competition each <code>-webhook-interval</code> (env <code>WEBHOOK_INTERVAL</code>, default 10m), bypassing the page cache, and diffs the fixtures against the previous poll by <code>match_id</code>; the first poll only records the snapshot. Webhooks and snapshots survive restarts when <code>-webhooks</code> (env <code>WEBHOOKS_FILE</code>) names a file.</p>
+    <p>All three endpoints require <code>Authorization: Bearer &lt;token&gt;</code> with the token set by <code>-admin-token</code> (env <code>ADMIN_TOKEN</code>); without one configured they answer 403. The <code>url</code> must resolve to public addresses only: loopback, private and link-local targets are rejected at registration and again when a delivery connects.</p>
+    <p>Body: <code>type</code>, one of <code>club_id</code> and <code>competition_id</code>, <code>url</code>, optional <code>events</code> (change kinds, default all) and <code>secret</code>. Without a secret one is generated; it is only returned in the registration response. Failed deliveries (network errors, non-2xx answers) are retried 5 times with exponential backoff; <code>GET /webhooks</code> shows the <code>last_delivery</code> of each webhook.</p>
+    <p>Each delivery is one changed match, signed with <code>X-Webhook-Signature: sha256=&lt;hex HMAC-SHA256 of the body keyed with the secret&gt;</code>; <code>X-Webhook-Event</code> and <code>X-Webhook-Delivery</code> repeat <code>event</code> and <code>delivery_id</code>.</p>
+    <details>
+      <summary>Delivery body</summary>
+      <pre>{
+  "delivery_id": "9c1f...",
+  "webhook_id": "4be0a1d2c3e4f506",
+  "event": "match.changed",
+  "sent_at": "2026-10-23T19:05:00Z",
+  "watch": { "type": "futsal", "club_id": "441d3783-06aa-436a-b438-359300ee0371" },
+  "changes": [ "rescheduled", "venue" ],
+  "competition_id": "f49e63bd-55d9-4c5e-93f7-8e482262b88f",
+  "competition_name": "2. Futsal liga - východ",
+  "match": { "date_time": "24.10.2026 18:00", "home": "...", "away": "...", "venue": "...", "status": "scheduled", "...": "..." },
+  "previous": { "date_time": "23.10.2026 19:00", "...": "..." }
+}</pre>
+    </details>
+  </section>
+
+  <section class="ep">
+    <h2>Match Report</h2>
+    <p><strong>GET</strong> <code>/match/{type}/{matchID}/report</code></p>
+    <p>Parses the IS "zápis o utkání" of a match (<code>match_id</code> from the club endpoint): lineups, goals, cards, substitutions, coaches, attendance and score. Every event's <code>side</code> is the team of its player, so an <code>own_goal</code> carries the side of the team that conceded it.</p>
+    <details>
+      <summary>Response shape</summary>
+      <pre>{
+  "match_id": "27a9299e-f18a-4ced-b9f9-a5086e789f34",
+  "report_url": "https://is.fotbal.cz/public/zapasy/zapis-o-utkani-report.aspx?zapas=...",
+  "facr_link": "https://www.fotbal.cz/futsal/zapasy/futsal/...",
+  "score": "3:1",
+  "half_time_score": "1:0",
+  "attendance": 120,
+  "home": {
+    "name": "FC Bizoni Uherské Hradiště, z.s.",
+    "coach": "Novák Jan",
+    "starters": [{ "number": "1", "name": "Dvořák Petr", "goalkeeper": true }],
+    "substitutes": [{ "number": "12", "name": "Svoboda Jiří" }]
+  },
+  "away": { "name": "FC Tango Hodonín", "starters": [], "substitutes": [] },
+  "goals": [{ "minute": 12, "side": "home", "number": "7", "player": "Král Tomáš", "penalty": true }],
+  "cards": [{ "minute": 33, "side": "away", "player": "Malý Adam", "card": "yellow" }],
+  "substitutions": [{ "minute": 20, "side": "home", "player_out": "Král Tomáš", "player_in": "Svoboda Jiří" }]
+}</pre>
+    </details>
+  </section>
+
+  <section class="ep">
+    <h2>Match Delegation (Referees)</h2>
+    <p><strong>GET</strong> <code>/match/{type}/{matchID}/delegation</code></p>
+    <p>Parses the IS delegation report: referee, assistant referees, delegate and other officials.</p>
+    <details>
+      <summary>Response shape</summary>
+      <pre>{
+  "match_id": "27a9299e-f18a-4ced-b9f9-a5086e789f34",
+  "delegation_url": "https://is.fotbal.cz/public/zapasy/zapas-delegace-report.aspx?zapas=...",
+  "referee": { "role": "referee", "label": "Rozhodčí", "name": "Novák Jan" },
+  "assistant_referees": [
+    { "role": "assistant_referee", "label": "AR1", "name": "Dvořák Petr" }
+  ],
+  "delegate": { "role": "delegate", "label": "Delegát", "name": "Svoboda Jiří" },
+  "officials": [ "... every official above, in page order ..." ]
+}</pre>
+    </details>
+  </section>
+
   <section class="ep">
     <h2>Shortcuts</h2>
     <p><strong>GET</strong> <code>/club/{id}</code> → redirects to <code>/club/football/{id}</code></p>
@@ -1101,6 +1352,12 @@ func docsHandler(w http.ResponseWriter, r *http.Request) {
 
   <footer>
     <p>Tip: Use a reverse proxy in production and set proper timeouts. This API scrapes public pages and may be rate-limited upstream.</p>
+    <p>Team logos carry their source: <code>team_id</code> (built from the club UUID), <code>search_exact</code> / <code>search_fuzzy</code> (club search hit) or <code>placeholder</code>.</p>
+    <p>Upstream pages are cached in memory and under <code>CACHE_DIR</code> (default: the user cache directory, <code>off</code> for memory only). TTLs: <code>CACHE_TTL_CLUB</code> (6h), <code>CACHE_TTL_TABLE</code> (30m), <code>CACHE_TTL_FIXTURES</code> (15m), <code>CACHE_TTL_REPORT</code> (15m), <code>CACHE_TTL_FINISHED_REPORT</code> (30 days), <code>CACHE_TTL_LOGO</code> (30 days). Responses carry <code>X-Cache: HIT|MISS|PARTIAL</code> and <code>Age</code> (seconds since the oldest cached page used was scraped).</p>
+    <p>Competitions of a club are scraped in parallel, at most <code>SCRAPE_CONCURRENCY</code> (default 4) at a time. A competition that fails keeps its place in the list and carries an <code>error</code> message.</p>
+    <p>Upstream requests can be tuned with <code>FETCH_TIMEOUT</code>, <code>FETCH_RETRIES</code>, <code>FETCH_BACKOFF</code>, <code>FETCH_HOST_DELAY</code> (Go durations, e.g. <code>15s</code>) and <code>FETCH_MAX_BYTES</code>.</p>
+    <p>The same scrapers run from the command line without the server: <code>facr-scraper search &lt;q&gt;</code>, <code>club</code>, <code>table</code> and <code>competition &lt;type&gt; &lt;id&gt;</code> print JSON (or a text table with <code>-format text</code>) to stdout. <code>facr-scraper -config site.json export -out dir</code> writes static JSON snapshots (club info, tables, competitions, <code>index.json</code>, <code>manifest.json</code>) of the clubs listed under <code>"clubs"</code>; club IDs must be UUIDs, and <code>scraped_at</code> in the manifest is when the (possibly cached) pages were fetched.</p>
+    <p>Upstream origins default to <code>https://www.fotbal.cz</code>, <code>https://is.fotbal.cz</code> and <code>https://is1.fotbal.cz</code>. Override them in a <code>-config</code> JSON file (<code>{"upstreams": {"fotbal": "...", "is": "...", "media": "..."}}</code>), with <code>FOTBAL_BASE_URL</code>, <code>IS_BASE_URL</code>, <code>MEDIA_BASE_URL</code> or with <code>-fotbal-url</code>, <code>-is-url</code>, <code>-media-url</code> (later wins). <code>-fixtures dir</code> serves recorded pages from a local fixture server instead of the internet; <code>-record dir</code> saves every fetched page in the same layout.</p>
   </footer>
 </body>
 </html>`)
@@ -1137,48 +1394,64 @@ func extractUUIDFromHref(href string) string {
 }
 
 type Match struct {
-    DateTime      string `json:"date_time"`
-    Home          string `json:"home"`
-    HomeID        string `json:"home_id,omitempty"`
-    HomeLogoURL   string `json:"home_logo_url,omitempty"`
-    Away          string `json:"away"`
-    AwayID        string `json:"away_id,omitempty"`
-    AwayLogoURL   string `json:"away_logo_url,omitempty"`
-    Score         string `json:"score"`
-    Venue         string `json:"venue"`
-    Note          string `json:"note,omitempty"`
-    MatchID       string `json:"match_id"`
-    ReportURL     string `json:"report_url,omitempty"`
-    FACRLink      string `json:"facr_link,omitempty"`
-    DelegationURL string `json:"delegation_url,omitempty"`
+    DateTime       string           `json:"date_time"`
+    Kickoff        string           `json:"kickoff,omitempty"`     // RFC 3339, Europe/Prague offset; empty when DateOnly
+    KickoffUTC     string           `json:"kickoff_utc,omitempty"` // RFC 3339, UTC
+    DateOnly       bool             `json:"date_only,omitempty"`   // time of day not published yet
+    Home           string           `json:"home"`
+    HomeID         string           `json:"home_id,omitempty"`
+    HomeLogoURL    string           `json:"home_logo_url,omitempty"`
+    HomeLogoSource string           `json:"home_logo_source,omitempty"`
+    Away           string           `json:"away"`
+    AwayID         string           `json:"away_id,omitempty"`
+    AwayLogoURL    string           `json:"away_logo_url,omitempty"`
+    AwayLogoSource string           `json:"away_logo_source,omitempty"`
+    Score          string           `json:"score"`
+    Status         MatchStatus      `json:"status"`
+    Venue          string           `json:"venue"`
+    Note           string           `json:"note,omitempty"` // postponement, cancellation, forfeit or bye
+    MatchID        string           `json:"match_id"`
+    ReportURL      string           `json:"report_url,omitempty"`
+    FACRLink       string           `json:"facr_link,omitempty"`
+    DelegationURL  string           `json:"delegation_url,omitempty"`
+    Officials      *MatchDelegation `json:"officials,omitempty"`
 }
 
 // TableRow represents one row in a standings table
 type TableRow struct {
-    Rank        string `json:"rank"`
-    Team        string `json:"team"`
-    TeamID      string `json:"team_id,omitempty"`
-    TeamLogoURL string `json:"team_logo_url,omitempty"`
-    Played      string `json:"played"`
-    Wins        string `json:"wins"`
-    Draws       string `json:"draws"`
-    Losses      string `json:"losses"`
-    Score       string `json:"score"`
-    Points      string `json:"points"`
+    Rank           string    `json:"rank"`
+    Team           string    `json:"team"`
+    TeamID         string    `json:"team_id,omitempty"`
+    TeamLogoURL    string    `json:"team_logo_url,omitempty"`
+    TeamLogoSource string    `json:"team_logo_source,omitempty"`
+    Played         string    `json:"played"`
+    Wins           string    `json:"wins"`
+    Draws          string    `json:"draws"`
+    Losses         string    `json:"losses"`
+    Score          string    `json:"score"`
+    Points         string    `json:"points"`
+    Form           *TeamForm `json:"form,omitempty"`
 }
 
-// resolveISURL makes relative IS links absolute against https://is.fotbal.cz/public/
+// resolveISURL makes IS links absolute against the IS origin's /public/ path.
+// Absolute links are moved onto the configured IS origin as well.
 func resolveISURL(href string) string {
 	href = strings.TrimSpace(href)
+	base, err := neturl.Parse(upstream.IS)
+	if err != nil {
+		return href
+	}
 	if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
 		if u, err := neturl.Parse(href); err == nil {
-			u.Scheme = "https"
-			u.Host = "is.fotbal.cz"
-			if !strings.HasPrefix(u.Path, "/public/") {
-				if strings.HasPrefix(u.Path, "/zapasy/") {
-					u.Path = "/public" + u.Path
+			path := strings.TrimPrefix(u.Path, base.Path)
+			if !strings.HasPrefix(path, "/public/") {
+				if strings.HasPrefix(path, "/zapasy/") {
+					path = "/public" + path
 				}
 			}
+			u.Scheme = base.Scheme
+			u.Host = base.Host
+			u.Path = base.Path + path
 			q := u.Query()
 			q.Del("discipline")
 			u.RawQuery = q.Encode()
@@ -1193,7 +1466,78 @@ func resolveISURL(href string) string {
 	if strings.HasPrefix(href, "/") {
 		href = strings.TrimPrefix(href, "/")
 	}
-	path := "/public/" + href
-	u := neturl.URL{Scheme: "https", Host: "is.fotbal.cz", Path: path}
+	// Keep the query string out of the path, otherwise "?" gets escaped as %3F
+	path, rawQuery, _ := strings.Cut(href, "?")
+	path = strings.TrimPrefix(path, "public/")
+	u := neturl.URL{Scheme: base.Scheme, Host: base.Host, Path: base.Path + "/public/" + path, RawQuery: rawQuery}
 	return u.String()
 }
+
+// sportParamFor maps the club type used in our routes to the IS "sport" parameter.
+func sportParamFor(clubType string) (string, bool) {
+	switch clubType {
+	case "football":
+		return "fotbal", true
+	case "futsal":
+		return "futsal", true
+	}
+	return "", false
+}
+
+// fotbalCompetitionURL builds the public fotbal.cz competition page (table and fixtures).
+func fotbalCompetitionURL(clubType, compID string) string {
+	if strings.EqualFold(clubType, "futsal") {
+		return fotbalURL("/futsal/futsal/table/%s", compID)
+	}
+	return fotbalURL("/souteze/turnaje/table/%s", compID)
+}
+
+// isCompetitionDetailURL builds the IS competition detail page listing all fixtures.
+func isCompetitionDetailURL(compID, sportParam string) string {
+	return isURL("/public/souteze/detail-souteze.aspx?req=%s&sport=%s", compID, sportParam)
+}
+
+// isCompetitionTableURL builds the IS standings page of a competition.
+func isCompetitionTableURL(compID, sportParam string) string {
+	return isURL("/public/souteze/tabulky-souteze.aspx?req=%s&sport=%s", compID, sportParam)
+}
+
+// facrMatchURL builds the canonical fotbal.cz match page for a match ID.
+func facrMatchURL(clubType, matchID string) string {
+	if matchID == "" {
+		return ""
+	}
+	if strings.EqualFold(clubType, "futsal") {
+		return fotbalURL("/futsal/zapasy/futsal/%s", matchID)
+	}
+	return fotbalURL("/souteze/zapasy/zapas/%s", matchID)
+}
+
+// queryFlag reports whether a boolean query parameter is switched on (?name=1, ?name=true, ?name).
+func queryFlag(r *http.Request, name string) bool {
+	vals, ok := r.URL.Query()[name]
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(vals[0])) {
+	case "", "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// collapseSpaces trims s and squashes runs of whitespace (including nbsp) into one space.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+var czechFolder = strings.NewReplacer(
+	"á", "a", "č", "c", "ď", "d", "é", "e", "ě", "e", "í", "i", "ň", "n", "ó", "o",
+	"ř", "r", "š", "s", "ť", "t", "ú", "u", "ů", "u", "ý", "y", "ž", "z",
+)
+
+// foldLabel lower-cases s and strips Czech diacritics so labels like
+// "Počet diváků" can be compared as "pocet divaku".
+func foldLabel(s string) string {
+	return czechFolder.Replace(strings.ToLower(collapseSpaces(s)))
+}
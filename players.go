@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ClubPlayers is the response of the players endpoint: one roster per
+// competition, since clubs field different teams in different competitions.
+type ClubPlayers struct {
+	ClubID   string       `json:"club_id"`
+	ClubType string       `json:"club_type"`
+	Name     string       `json:"name"`
+	Teams    []TeamRoster `json:"teams"`
+}
+
+// TeamRoster is the squad of the club's team in one competition, built from
+// the match reports of its played matches.
+type TeamRoster struct {
+	CompetitionID   string         `json:"competition_id"`
+	CompetitionCode string         `json:"competition_code,omitempty"`
+	CompetitionName string         `json:"competition_name"`
+	Team            string         `json:"team,omitempty"` // team name in the reports
+	Reports         int            `json:"reports"`        // match reports the roster is built from
+	FailedReports   int            `json:"failed_reports,omitempty"`
+	Players         []RosterPlayer `json:"players"`
+	Error           string         `json:"error,omitempty"`
+}
+
+// RosterPlayer is one player of a roster.
+type RosterPlayer struct {
+	Name        string   `json:"name"`
+	Numbers     []string `json:"numbers,omitempty"` // shirt numbers used, in order of first use
+	Squad       int      `json:"squad"`             // reports listing the player
+	Appearances int      `json:"appearances"`       // started or came on
+	Starts      int      `json:"starts"`
+	Goalkeeper  bool     `json:"goalkeeper,omitempty"` // listed as goalkeeper at least once
+}
+
+// playedReport is a played match with its parsed report.
+type playedReport struct {
+	Match  Match
+	Side   string // the club's side, "home" or "away"; empty for all matches of a competition
+	Report *MatchReport
+}
+
+// team returns the club's side of the report.
+func (c playedReport) team() TeamReport {
+	if c.Side == "away" {
+		return c.Report.Away
+	}
+	return c.Report.Home
+}
+
+// competitionReports holds the reports of one competition of a club.
+type competitionReports struct {
+	Competition Competition
+	Reports     []playedReport
+	Failed      int
+}
+
+// getClubPlayers returns the club's players per competition from the match reports
+func getClubPlayers(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clubID := vars["id"]
+	clubType := vars["type"]
+	if clubID == "" {
+		http.Error(w, "Club ID is required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := sportParamFor(clubType); !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+
+	club, comps, err := collectClubReports(r.Context(), clubType, clubID, competitionFilterFromRequest(r), time.Now())
+	if err != nil {
+		writeFetchError(w, "club data", err)
+		return
+	}
+	players := &ClubPlayers{ClubID: clubID, ClubType: clubType, Name: club.Name, Teams: []TeamRoster{}}
+	for _, comp := range comps {
+		players.Teams = append(players.Teams, teamRoster(comp, clubType))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(players)
+}
+
+// collectClubReports scrapes the played matches of the club's competitions
+// (restricted by filter, see competitionSelected) and fetches their match
+// reports. Reports of finished matches are cached, so repeated calls only
+// fetch the fixture lists.
+func collectClubReports(ctx context.Context, clubType, clubID string, filter []string, now time.Time) (*ClubInfo, []competitionReports, error) {
+	club, err := scrapeClubPage(ctx, clubType, clubID)
+	if err != nil {
+		return nil, nil, err
+	}
+	sportParam, _ := sportParamFor(clubType)
+
+	var comps []competitionReports
+	for _, comp := range club.Competitions {
+		if len(filter) == 0 || competitionSelected(comp, filter) {
+			comps = append(comps, competitionReports{Competition: comp})
+		}
+	}
+	forEachLimited(len(comps), scrapeConcurrency, func(i int) {
+		comp := &comps[i].Competition
+		matches, err := competitionMatches(ctx, *comp, clubType, sportParam, club.Name, clubID)
+		if err != nil {
+			log.Printf("error fetching matches for %s: %v", comp.ID, err)
+			comp.Error = err.Error()
+			return
+		}
+		for _, m := range matches {
+			if m.MatchID == "" || !matchPlayed(m, now) || m.Status == StatusForfeited {
+				continue
+			}
+			side := "away"
+			if sameTeam(clubID, club.Name, m.HomeID, m.Home) {
+				side = "home"
+			}
+			comps[i].Reports = append(comps[i].Reports, playedReport{Match: m, Side: side})
+		}
+	})
+
+	fetchPlayedReports(ctx, clubType, comps)
+	return club, comps, nil
+}
+
+// fetchPlayedReports fetches the report of every match of comps. Matches
+// whose report failed are dropped and counted in Failed.
+func fetchPlayedReports(ctx context.Context, clubType string, comps []competitionReports) {
+	// Reports of all competitions share one worker pool
+	var pending []*playedReport
+	for i := range comps {
+		for j := range comps[i].Reports {
+			pending = append(pending, &comps[i].Reports[j])
+		}
+	}
+	forEachLimited(len(pending), scrapeConcurrency, func(i int) {
+		c := pending[i]
+		report, err := fetchMatchReport(ctx, clubType, c.Match.MatchID)
+		if err != nil {
+			log.Printf("error fetching match report %s: %v", c.Match.MatchID, err)
+			return
+		}
+		c.Report = report
+	})
+	for i := range comps {
+		fetched := comps[i].Reports[:0]
+		for _, c := range comps[i].Reports {
+			if c.Report != nil {
+				fetched = append(fetched, c)
+			} else {
+				comps[i].Failed++
+			}
+		}
+		comps[i].Reports = fetched
+	}
+}
+
+// teamRoster aggregates the club's lineups of one competition. In futsal
+// substitutions are rolling and not recorded, so every listed player counts
+// as an appearance; in football a substitute needs a substitution.
+func teamRoster(comp competitionReports, clubType string) TeamRoster {
+	roster := TeamRoster{
+		CompetitionID:   comp.Competition.ID,
+		CompetitionCode: comp.Competition.Code,
+		CompetitionName: comp.Competition.Name,
+		Reports:         len(comp.Reports),
+		FailedReports:   comp.Failed,
+		Players:         []RosterPlayer{},
+		Error:           comp.Competition.Error,
+	}
+	rolling := strings.EqualFold(clubType, "futsal")
+	byName := map[string]*RosterPlayer{}
+	var order []string
+	for _, c := range comp.Reports {
+		team := c.team()
+		if roster.Team == "" {
+			roster.Team = team.Name
+		}
+		add := func(p ReportPlayer, started bool) {
+			key := foldLabel(p.Name)
+			rp, ok := byName[key]
+			if !ok {
+				rp = &RosterPlayer{Name: p.Name}
+				byName[key] = rp
+				order = append(order, key)
+			}
+			rp.Squad++
+			if p.Number != "" && !slices.Contains(rp.Numbers, p.Number) {
+				rp.Numbers = append(rp.Numbers, p.Number)
+			}
+			if p.Goalkeeper {
+				rp.Goalkeeper = true
+			}
+			if started {
+				rp.Starts++
+			}
+			if started || rolling || cameOn(c.Report, c.Side, p) {
+				rp.Appearances++
+			}
+		}
+		for _, p := range team.Starters {
+			add(p, true)
+		}
+		for _, p := range team.Substitutes {
+			add(p, false)
+		}
+	}
+	for _, key := range order {
+		roster.Players = append(roster.Players, *byName[key])
+	}
+	sort.SliceStable(roster.Players, func(i, j int) bool {
+		a, b := roster.Players[i], roster.Players[j]
+		if a.Appearances != b.Appearances {
+			return a.Appearances > b.Appearances
+		}
+		return a.Name < b.Name
+	})
+	return roster
+}
+
+// cameOn reports whether substitute p was brought on for side.
+func cameOn(report *MatchReport, side string, p ReportPlayer) bool {
+	for _, s := range report.Substitutions {
+		if (s.Side == "" || s.Side == side) && samePlayer(p, s.NumberIn, s.PlayerIn) {
+			return true
+		}
+	}
+	return false
+}
+
+// samePlayer matches a lineup player against an event's shirt number and
+// name; events often give the surname only.
+func samePlayer(p ReportPlayer, number, name string) bool {
+	if number != "" && p.Number != "" {
+		return number == p.Number
+	}
+	a, b := foldLabel(p.Name), foldLabel(name)
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || strings.HasSuffix(a, " "+b) || strings.HasSuffix(b, " "+a)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTeamRoster(t *testing.T) {
+	const sub = "<td>12 Ondřej Malý za 7 Petr Dvořák</td>"
+	novák := RosterPlayer{Name: "Jan Novák", Numbers: []string{"1"}, Squad: 1, Appearances: 1, Starts: 1, Goalkeeper: true}
+	dvořák := RosterPlayer{Name: "Petr Dvořák", Numbers: []string{"7"}, Squad: 1, Appearances: 1, Starts: 1}
+	malý := RosterPlayer{Name: "Ondřej Malý", Numbers: []string{"12"}, Squad: 1}
+	// Sorted by appearances, then by name
+	played := []RosterPlayer{novák, {Name: malý.Name, Numbers: malý.Numbers, Squad: 1, Appearances: 1}, dvořák}
+	benched := []RosterPlayer{novák, dvořák, malý}
+	tests := []struct {
+		name     string
+		html     string
+		clubType string
+		want     []RosterPlayer
+	}{
+		{"substitute brought on", reportTablesHTML, "football", played},
+		{"unused substitute", strings.Replace(reportTablesHTML, sub, "<td></td>", 1), "football", benched},
+		{"futsal rolling substitutions", strings.Replace(reportTablesHTML, sub, "<td></td>", 1), "futsal", played},
+		{"substitution by surname", strings.Replace(reportTablesHTML, sub, "<td>Malý za Dvořák</td>", 1), "football", played},
+		// A shirt number decides over the name
+		{"other number", strings.Replace(reportTablesHTML, sub, "<td>14 Ondřej Malý za 7 Petr Dvořák</td>", 1), "football", benched},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := parseMatchReport(parseHTML(t, tt.html), tt.clubType)
+			comp := competitionReports{
+				Competition: Competition{ID: "c", Name: "Krajský přebor"},
+				Reports:     []playedReport{{Side: "home", Report: report}},
+			}
+			roster := teamRoster(comp, tt.clubType)
+			if roster.Team != "FC Domov" || roster.Reports != 1 {
+				t.Errorf("team = %q, reports = %d", roster.Team, roster.Reports)
+			}
+			if !reflect.DeepEqual(roster.Players, tt.want) {
+				t.Errorf("players =\n%+v\nwant\n%+v", roster.Players, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamePlayer(t *testing.T) {
+	p := ReportPlayer{Number: "12", Name: "Ondřej Malý"}
+	tests := []struct {
+		number, name string
+		want         bool
+	}{
+		{"12", "", true},
+		{"12", "Someone Else", true},
+		{"14", "Ondřej Malý", false},
+		{"", "Ondřej Malý", true},
+		{"", "MALÝ", true},
+		{"", "Maly", true},
+		{"", "O. Malý", false},
+		{"", "Malý Ondřej", false},
+		{"", "Ondřej", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := samePlayer(p, tt.number, tt.name); got != tt.want {
+			t.Errorf("samePlayer(%q, %q) = %v, want %v", tt.number, tt.name, got, tt.want)
+		}
+	}
+	// Without a lineup number the name decides
+	if !samePlayer(ReportPlayer{Name: "Ondřej Malý"}, "12", "Malý") {
+		t.Error("name match ignored when the lineup has no number")
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import "sync"
+
+// scrapeConcurrency bounds how many competitions (or reports) are scraped at
+// once per request; set with SCRAPE_CONCURRENCY. The fetcher still spaces
+// requests per host, so this mostly overlaps waiting on upstream.
+var scrapeConcurrency = envInt("SCRAPE_CONCURRENCY", 4)
+
+// forEachLimited calls fn(i) for every i in [0, n) on at most limit
+// goroutines and returns when all calls are done. Callers write results by
+// index, which keeps output order independent of completion order.
+func forEachLimited(n, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
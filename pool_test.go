@@ -0,0 +1,46 @@
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEachLimited(t *testing.T) {
+	for _, tt := range []struct {
+		n, limit, max int
+	}{
+		{20, 3, 3},
+		{5, 10, 5},
+		{6, 0, 1}, // limit < 1 runs one at a time
+		{6, -2, 1},
+		{0, 4, 0},
+	} {
+		var running, peak atomic.Int32
+		results := make([]int, tt.n)
+		forEachLimited(tt.n, tt.limit, func(i int) {
+			now := running.Add(1)
+			for {
+				p := peak.Load()
+				if now <= p || peak.CompareAndSwap(p, now) {
+					break
+				}
+			}
+			// Later indexes finish first
+			time.Sleep(time.Duration(tt.n-i) * time.Millisecond)
+			results[i] = i * i
+			running.Add(-1)
+		})
+		if int(peak.Load()) > tt.max {
+			t.Errorf("n=%d limit=%d: %d calls at once, want at most %d", tt.n, tt.limit, peak.Load(), tt.max)
+		}
+		if tt.max > 1 && tt.n > tt.max && int(peak.Load()) < 2 {
+			t.Errorf("n=%d limit=%d: calls never overlapped", tt.n, tt.limit)
+		}
+		for i, r := range results {
+			if r != i*i {
+				t.Errorf("n=%d limit=%d: result %d = %d, want %d", tt.n, tt.limit, i, r, i*i)
+			}
+		}
+	}
+}
@@ -0,0 +1,602 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Referee is one match official with the matches they were delegated to.
+type Referee struct {
+	ID           string               `json:"id,omitempty"`
+	Name         string               `json:"name"`
+	Matches      int                  `json:"matches"`  // played matches officiated in any role
+	Upcoming     int                  `json:"upcoming"` // delegated, not played yet
+	Cards        *RefereeCards        `json:"cards,omitempty"`
+	Competitions []RefereeCompetition `json:"competitions"`
+	Assignments  []RefereeAssignment  `json:"assignments"`
+}
+
+// RefereeCards counts the cards of the matches a referee led, over the
+// matches whose report has been parsed.
+type RefereeCards struct {
+	Reports int `json:"reports"`
+	Yellow  int `json:"yellow"`
+	Red     int `json:"red"`
+}
+
+// RefereeCompetition is the referee's record in one competition.
+type RefereeCompetition struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name,omitempty"`
+	Matches int           `json:"matches"`
+	Cards   *RefereeCards `json:"cards,omitempty"`
+}
+
+// RefereeAssignment is one match a referee was delegated to.
+type RefereeAssignment struct {
+	Type            string        `json:"type"`
+	CompetitionID   string        `json:"competition_id"`
+	CompetitionName string        `json:"competition_name,omitempty"`
+	MatchID         string        `json:"match_id"`
+	DateTime        string        `json:"date_time"`
+	Kickoff         string        `json:"kickoff,omitempty"`
+	Home            string        `json:"home"`
+	Away            string        `json:"away"`
+	Score           string        `json:"score,omitempty"`
+	Status          MatchStatus   `json:"status"`
+	Role            string        `json:"role"`
+	Cards           *RefereeCards `json:"cards,omitempty"` // only for the leading referee
+}
+
+// RefereeSearch is the response of the referee lookup endpoint.
+type RefereeSearch struct {
+	Query          string    `json:"query"`
+	IndexedMatches int       `json:"indexed_matches"`
+	Referees       []Referee `json:"referees"`
+}
+
+// ClubReferees is the response of the club referees endpoint.
+type ClubReferees struct {
+	ClubID   string    `json:"club_id"`
+	ClubType string    `json:"club_type"`
+	Name     string    `json:"name"`
+	Errors   []string  `json:"errors,omitempty"` // competitions that could not be scraped
+	Referees []Referee `json:"referees"`
+}
+
+// refereeRoles are the officials kept in the referee index; delegates,
+// timekeepers and observers are left out.
+var refereeRoles = map[string]bool{"referee": true, "assistant_referee": true, "fourth_official": true, "var": true}
+
+// refereeIndex remembers the delegation of every match whose delegation
+// report the server has read, so referees can be looked up across clubs and
+// competitions. It is optionally persisted to a JSON file; without one it is
+// rebuilt on demand after a restart.
+type refereeIndex struct {
+	mu      sync.Mutex
+	path    string
+	matches map[string]*refereeMatch // by match ID
+	scanned map[string]time.Time     // by competition key, when its matches were last indexed
+}
+
+type refereeMatch struct {
+	Type      string        `json:"type"`
+	Match     watchedMatch  `json:"match"`
+	Officials []Official    `json:"officials"`
+	Cards     *RefereeCards `json:"cards,omitempty"` // nil until the match report was parsed
+}
+
+// refereeFile is the persisted state of the index.
+type refereeFile struct {
+	Matches []*refereeMatch `json:"matches"`
+}
+
+var referees = &refereeIndex{matches: map[string]*refereeMatch{}}
+
+// load reads the index from path, which is also where it is saved later. A
+// missing file is an empty index.
+func (ix *refereeIndex) load(path string) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var f refereeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for _, e := range f.Matches {
+		ix.matches[e.Match.MatchID] = e
+	}
+	return nil
+}
+
+// save writes the index file after a batch of changes.
+func (ix *refereeIndex) save() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.path == "" {
+		return
+	}
+	f := refereeFile{Matches: make([]*refereeMatch, 0, len(ix.matches))}
+	for _, e := range ix.matches {
+		f.Matches = append(f.Matches, e)
+	}
+	sort.Slice(f.Matches, func(i, j int) bool { return f.Matches[i].Match.MatchID < f.Matches[j].Match.MatchID })
+	body, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		log.Printf("referees: %v", err)
+		return
+	}
+	// Write and rename, so a crash never leaves a truncated file behind
+	tmp := ix.path + ".tmp"
+	err = os.MkdirAll(filepath.Dir(ix.path), 0o755)
+	if err == nil {
+		err = os.WriteFile(tmp, body, 0o600)
+	}
+	if err == nil {
+		err = os.Rename(tmp, ix.path)
+	}
+	if err != nil {
+		log.Printf("referees: saving %s: %v", ix.path, err)
+	}
+}
+
+// settled reports whether the delegation of a played match is indexed
+// already; it does not change any more.
+func (ix *refereeIndex) settled(id string, now time.Time) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	e := ix.matches[id]
+	return e != nil && matchPlayed(e.Match.Match, now)
+}
+
+// competitionDue reports whether the competition key is to be indexed again
+// and, if so, marks it indexed at now. A competition is read at most once per
+// fixtures TTL, so lookups do not scrape it again and again.
+func (ix *refereeIndex) competitionDue(key string, now time.Time) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if last, ok := ix.scanned[key]; ok && now.Sub(last) < activeTTLs.Fixtures {
+		return false
+	}
+	if ix.scanned == nil {
+		ix.scanned = map[string]time.Time{}
+	}
+	ix.scanned[key] = now
+	return true
+}
+
+// competitionFailed forgets that key was indexed, so the next lookup retries it.
+func (ix *refereeIndex) competitionFailed(key string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	delete(ix.scanned, key)
+}
+
+// add records the delegation of m, replacing an older one of the same match.
+func (ix *refereeIndex) add(clubType string, m watchedMatch, d *MatchDelegation) {
+	var officials []Official
+	for _, o := range d.Officials {
+		if refereeRoles[o.Role] {
+			officials = append(officials, o)
+		}
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	entry := &refereeMatch{Type: clubType, Match: m, Officials: officials}
+	if prev, ok := ix.matches[m.MatchID]; ok {
+		entry.Cards = prev.Cards
+	}
+	ix.matches[m.MatchID] = entry
+}
+
+// withCards counts the cards of every played match of ids from its report.
+// Without fetch only reports that are cached already are used.
+func (ix *refereeIndex) withCards(ctx context.Context, ids []string, fetch bool, now time.Time) {
+	var pending []*refereeMatch
+	ix.mu.Lock()
+	for _, id := range ids {
+		if e := ix.matches[id]; e != nil && e.Cards == nil && matchPlayed(e.Match.Match, now) && e.Match.Status != StatusForfeited {
+			pending = append(pending, e)
+		}
+	}
+	ix.mu.Unlock()
+
+	cards := make([]*RefereeCards, len(pending))
+	forEachLimited(len(pending), scrapeConcurrency, func(i int) {
+		e := pending[i]
+		report := cachedMatchReport(ctx, e.Type, e.Match.MatchID)
+		if report == nil && fetch {
+			var err error
+			if report, err = fetchMatchReport(ctx, e.Type, e.Match.MatchID); err != nil {
+				log.Printf("error fetching match report %s: %v", e.Match.MatchID, err)
+			}
+		}
+		if report == nil {
+			return
+		}
+		c := &RefereeCards{Reports: 1}
+		for _, card := range report.Cards {
+			if card.Card == "red" {
+				c.Red++
+			} else {
+				c.Yellow++
+			}
+		}
+		cards[i] = c
+	})
+
+	ix.mu.Lock()
+	changed := false
+	for i, e := range pending {
+		if cards[i] != nil {
+			e.Cards = cards[i]
+			changed = true
+		}
+	}
+	ix.mu.Unlock()
+	if changed {
+		ix.save()
+	}
+}
+
+// find returns the referees matching query: an IS person ID, a full name or
+// a part of a name (case and diacritics are ignored).
+func (ix *refereeIndex) find(query string, now time.Time) ([]Referee, int) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	q := foldLabel(query)
+	var exact, partial []*refereeMatch
+	for _, e := range ix.matches {
+		for _, o := range e.Officials {
+			name := foldLabel(o.Name)
+			switch {
+			case o.ID != "" && o.ID == strings.TrimSpace(query), name == q:
+				exact = append(exact, e)
+			case q != "" && strings.Contains(name, q):
+				partial = append(partial, e)
+			}
+		}
+	}
+	// A partial name is only used when nobody matches exactly
+	if len(exact) > 0 {
+		return buildReferees(exact, func(o Official) bool {
+			return (o.ID != "" && o.ID == strings.TrimSpace(query)) || foldLabel(o.Name) == q
+		}, now), len(ix.matches)
+	}
+	return buildReferees(partial, func(o Official) bool {
+		return q != "" && strings.Contains(foldLabel(o.Name), q)
+	}, now), len(ix.matches)
+}
+
+// forMatches returns every referee of the indexed matches of ids.
+func (ix *refereeIndex) forMatches(ids []string, now time.Time) []Referee {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	var entries []*refereeMatch
+	for _, id := range ids {
+		if e := ix.matches[id]; e != nil {
+			entries = append(entries, e)
+		}
+	}
+	return buildReferees(entries, func(Official) bool { return true }, now)
+}
+
+// buildReferees groups the officials selected by keep by person: the IS ID
+// when published, else the name. Entries may repeat; every match counts once.
+func buildReferees(entries []*refereeMatch, keep func(Official) bool, now time.Time) []Referee {
+	byKey := map[string]*Referee{}
+	seen := map[string]bool{}
+	var order []string
+	for _, e := range entries {
+		for _, o := range e.Officials {
+			if !keep(o) {
+				continue
+			}
+			key := "name:" + foldLabel(o.Name)
+			if o.ID != "" {
+				key = o.ID
+			}
+			if seen[key+"|"+e.Match.MatchID] {
+				continue
+			}
+			seen[key+"|"+e.Match.MatchID] = true
+			ref, ok := byKey[key]
+			if !ok {
+				ref = &Referee{ID: o.ID, Name: o.Name, Competitions: []RefereeCompetition{}, Assignments: []RefereeAssignment{}}
+				byKey[key] = ref
+				order = append(order, key)
+			}
+			ref.add(e, o.Role, now)
+		}
+	}
+
+	list := []Referee{}
+	for _, key := range order {
+		ref := byKey[key]
+		sort.SliceStable(ref.Assignments, func(i, j int) bool {
+			ti, _, _ := parseKickoff(ref.Assignments[i].DateTime)
+			tj, _, _ := parseKickoff(ref.Assignments[j].DateTime)
+			return ti.Before(tj)
+		})
+		list = append(list, *ref)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Matches != list[j].Matches {
+			return list[i].Matches > list[j].Matches
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// add records one assignment; cards count only where ref led the match.
+func (ref *Referee) add(e *refereeMatch, role string, now time.Time) {
+	m := e.Match
+	a := RefereeAssignment{
+		Type:            e.Type,
+		CompetitionID:   m.CompetitionID,
+		CompetitionName: m.CompetitionName,
+		MatchID:         m.MatchID,
+		DateTime:        m.DateTime,
+		Kickoff:         m.Kickoff,
+		Home:            m.Home,
+		Away:            m.Away,
+		Score:           m.Score,
+		Status:          m.Status,
+		Role:            role,
+	}
+	if role == "referee" && e.Cards != nil {
+		a.Cards = e.Cards
+	}
+	ref.Assignments = append(ref.Assignments, a)
+	if !matchPlayed(m.Match, now) {
+		if m.Status == StatusScheduled {
+			ref.Upcoming++
+		}
+		return
+	}
+
+	ref.Matches++
+	var comp *RefereeCompetition
+	for i := range ref.Competitions {
+		if ref.Competitions[i].ID == m.CompetitionID {
+			comp = &ref.Competitions[i]
+		}
+	}
+	if comp == nil {
+		ref.Competitions = append(ref.Competitions, RefereeCompetition{ID: m.CompetitionID, Name: m.CompetitionName})
+		comp = &ref.Competitions[len(ref.Competitions)-1]
+	}
+	comp.Matches++
+	if a.Cards != nil {
+		comp.Cards = addCards(comp.Cards, a.Cards)
+		ref.Cards = addCards(ref.Cards, a.Cards)
+	}
+}
+
+func addCards(total, c *RefereeCards) *RefereeCards {
+	if total == nil {
+		total = &RefereeCards{}
+	}
+	total.Reports += c.Reports
+	total.Yellow += c.Yellow
+	total.Red += c.Red
+	return total
+}
+
+// indexDelegations fetches the delegation reports of the IS matches among
+// matches and adds them to the referee index. Played matches that are
+// indexed already are skipped.
+func indexDelegations(ctx context.Context, clubType string, matches []watchedMatch) {
+	now := time.Now()
+	var pending []watchedMatch
+	for _, m := range matches {
+		if m.MatchID != "" && m.DelegationURL != "" && m.Status != StatusBye && !referees.settled(m.MatchID, now) {
+			pending = append(pending, m)
+		}
+	}
+	forEachLimited(len(pending), scrapeConcurrency, func(i int) {
+		m := pending[i]
+		delegation, err := fetchMatchDelegation(ctx, m.MatchID)
+		if err != nil {
+			log.Printf("delegation fetch error for %s: %v", m.MatchID, err)
+			return
+		}
+		referees.add(clubType, m, delegation)
+	})
+	if len(pending) > 0 {
+		referees.save()
+	}
+}
+
+// indexClubs adds the delegations of every match in the competitions of
+// clubs to the referee index. Each competition is scraped once, and not
+// again before the fixtures TTL has passed.
+func indexClubs(ctx context.Context, clubs []ClubRef) {
+	now := time.Now()
+	pages := make([]*ClubInfo, len(clubs))
+	forEachLimited(len(clubs), scrapeConcurrency, func(i int) {
+		club, err := scrapeClubPage(ctx, clubs[i].Type, clubs[i].ID)
+		if err != nil {
+			log.Printf("error fetching club %s: %v", clubs[i].ID, err)
+			return
+		}
+		pages[i] = club
+	})
+	type scan struct {
+		clubType string
+		comp     Competition
+	}
+	var scans []scan
+	seen := map[string]bool{}
+	for i, club := range pages {
+		if club == nil {
+			continue
+		}
+		for _, comp := range club.Competitions {
+			if key := clubs[i].Type + ":" + comp.ID; !seen[key] {
+				seen[key] = true
+				if referees.competitionDue(key, now) {
+					scans = append(scans, scan{clubType: clubs[i].Type, comp: comp})
+				}
+			}
+		}
+	}
+	forEachLimited(len(scans), scrapeConcurrency, func(i int) {
+		s := scans[i]
+		sportParam, _ := sportParamFor(s.clubType)
+		found, err := competitionMatches(ctx, s.comp, s.clubType, sportParam, "", "")
+		if err != nil {
+			log.Printf("error fetching matches for %s: %v", s.comp.ID, err)
+			referees.competitionFailed(s.clubType + ":" + s.comp.ID)
+			return
+		}
+		matches := make([]watchedMatch, len(found))
+		for j, m := range found {
+			matches[j] = watchedMatch{Match: m, CompetitionID: s.comp.ID, CompetitionName: s.comp.Name}
+		}
+		indexDelegations(ctx, s.clubType, matches)
+	})
+}
+
+// getReferee looks a referee up by IS person ID or name in the referee index.
+// ?type= with ?competition= indexes the given competitions first, otherwise
+// the competitions of the configured clubs are; either way a competition is
+// indexed again only once the fixtures TTL has passed.
+func getReferee(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(mux.Vars(r)["query"])
+	if query == "" {
+		http.Error(w, "Referee name or ID is required", http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	now := time.Now()
+	if comps := competitionFilterFromRequest(r); len(comps) > 0 {
+		clubType := r.URL.Query().Get("type")
+		sportParam, ok := sportParamFor(clubType)
+		if !ok {
+			http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+			return
+		}
+		var matches []watchedMatch
+		var mu sync.Mutex
+		forEachLimited(len(comps), scrapeConcurrency, func(i int) {
+			key := clubType + ":" + comps[i]
+			if !referees.competitionDue(key, now) {
+				return
+			}
+			comp := Competition{ID: comps[i], MatchesLink: fotbalCompetitionURL(clubType, comps[i])}
+			found, err := competitionMatches(ctx, comp, clubType, sportParam, "", "")
+			if err != nil {
+				log.Printf("error fetching matches for %s: %v", comp.ID, err)
+				referees.competitionFailed(key)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, m := range found {
+				matches = append(matches, watchedMatch{Match: m, CompetitionID: comp.ID})
+			}
+		})
+		indexDelegations(ctx, clubType, matches)
+	} else {
+		indexClubs(ctx, configClubs)
+	}
+
+	found, _ := referees.find(query, now)
+	var ids []string
+	for _, ref := range found {
+		for _, a := range ref.Assignments {
+			ids = append(ids, a.MatchID)
+		}
+	}
+	referees.withCards(ctx, ids, queryFlag(r, "cards"), now)
+	search := RefereeSearch{Query: query}
+	search.Referees, search.IndexedMatches = referees.find(query, now)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(search)
+}
+
+// getClubReferees lists the referees delegated to the club's matches this season
+func getClubReferees(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clubID := vars["id"]
+	clubType := vars["type"]
+	if clubID == "" {
+		http.Error(w, "Club ID is required", http.StatusBadRequest)
+		return
+	}
+	sportParam, ok := sportParamFor(clubType)
+	if !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	club, err := scrapeClubPage(ctx, clubType, clubID)
+	if err != nil {
+		writeFetchError(w, "club data", err)
+		return
+	}
+
+	filter := competitionFilterFromRequest(r)
+	var comps []Competition
+	for _, comp := range club.Competitions {
+		if len(filter) == 0 || competitionSelected(comp, filter) {
+			comps = append(comps, comp)
+		}
+	}
+	found := make([][]watchedMatch, len(comps))
+	errs := make([]string, len(comps))
+	forEachLimited(len(comps), scrapeConcurrency, func(i int) {
+		comp := comps[i]
+		matches, err := competitionMatches(ctx, comp, clubType, sportParam, club.Name, clubID)
+		if err != nil {
+			log.Printf("error fetching matches for %s: %v", comp.ID, err)
+			errs[i] = comp.ID + ": " + err.Error()
+			return
+		}
+		for _, m := range matches {
+			found[i] = append(found[i], watchedMatch{Match: m, CompetitionID: comp.ID, CompetitionName: comp.Name})
+		}
+	})
+
+	resp := &ClubReferees{ClubID: clubID, ClubType: clubType, Name: club.Name}
+	var matches []watchedMatch
+	var ids []string
+	for i := range comps {
+		if errs[i] != "" {
+			resp.Errors = append(resp.Errors, errs[i])
+		}
+		for _, m := range found[i] {
+			matches = append(matches, m)
+			ids = append(ids, m.MatchID)
+		}
+	}
+	indexDelegations(ctx, clubType, matches)
+	now := time.Now()
+	referees.withCards(ctx, ids, queryFlag(r, "cards"), now)
+	resp.Referees = referees.forMatches(ids, now)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRefereeIndexFromConfiguredClubs(t *testing.T) {
+	defer func(clubs []ClubRef, ix *refereeIndex) { configClubs, referees = clubs, ix }(configClubs, referees)
+	path := filepath.Join(t.TempDir(), "referees.json")
+	referees = &refereeIndex{matches: map[string]*refereeMatch{}}
+	if err := referees.load(path); err != nil {
+		t.Fatal(err)
+	}
+	configClubs = []ClubRef{{Type: "futsal", ID: bizoniID}}
+
+	// The lookup indexes the configured clubs itself
+	var search RefereeSearch
+	getJSON(t, "/referees/sudi", &search)
+	if search.IndexedMatches == 0 || len(search.Referees) == 0 || search.Referees[0].ID != "87000001" {
+		t.Fatalf("search = %+v", search)
+	}
+
+	// Within the fixtures TTL a further lookup reads only the club page
+	before := pages.count.Load()
+	getJSON(t, "/referees/sudi", &search)
+	if n := pages.count.Load() - before; n != 1 {
+		t.Errorf("second lookup fetched %d pages, want just the club page", n)
+	}
+	// and once it has passed, the competitions again
+	for key := range referees.scanned {
+		referees.scanned[key] = time.Now().Add(-activeTTLs.Fixtures)
+	}
+	before = pages.count.Load()
+	getJSON(t, "/referees/sudi", &search)
+	if n := pages.count.Load() - before; n <= 1 {
+		t.Errorf("lookup after the TTL fetched %d pages, want the competitions too", n)
+	}
+
+	// and a restart finds it in the saved file
+	reloaded := &refereeIndex{matches: map[string]*refereeMatch{}}
+	if err := reloaded.load(path); err != nil {
+		t.Fatal(err)
+	}
+	found, indexed := reloaded.find("87000001", time.Now())
+	if indexed != search.IndexedMatches || len(found) != 1 || found[0].Name != "Petr Sudí" {
+		t.Errorf("reloaded index: %d matches, referees %+v", indexed, found)
+	}
+}
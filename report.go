@@ -0,0 +1,723 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	neturl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gorilla/mux"
+)
+
+// ReportPlayer is one player listed in a lineup of the match report
+type ReportPlayer struct {
+	Number     string `json:"number,omitempty"`
+	Name       string `json:"name"`
+	Captain    bool   `json:"captain,omitempty"`
+	Goalkeeper bool   `json:"goalkeeper,omitempty"`
+}
+
+// TeamReport holds one side of the match report
+type TeamReport struct {
+	Name        string         `json:"name,omitempty"`
+	Coach       string         `json:"coach,omitempty"`
+	Starters    []ReportPlayer `json:"starters,omitempty"`
+	Substitutes []ReportPlayer `json:"substitutes,omitempty"`
+}
+
+// ReportGoal is a goal from the report; Side is "home" or "away", the team of
+// the player, so an own goal's Side is the team conceding it
+type ReportGoal struct {
+	Minute   int    `json:"minute"`
+	Stoppage int    `json:"stoppage,omitempty"`
+	Side     string `json:"side,omitempty"`
+	Number   string `json:"number,omitempty"`
+	Player   string `json:"player"`
+	Penalty  bool   `json:"penalty,omitempty"`
+	OwnGoal  bool   `json:"own_goal,omitempty"`
+}
+
+// ReportCard is a yellow or red card from the report
+type ReportCard struct {
+	Minute   int    `json:"minute"`
+	Stoppage int    `json:"stoppage,omitempty"`
+	Side     string `json:"side,omitempty"`
+	Number   string `json:"number,omitempty"`
+	Player   string `json:"player"`
+	Card     string `json:"card"` // yellow or red
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ReportSubstitution is one substitution from the report
+type ReportSubstitution struct {
+	Minute    int    `json:"minute"`
+	Stoppage  int    `json:"stoppage,omitempty"`
+	Side      string `json:"side,omitempty"`
+	NumberOut string `json:"number_out,omitempty"`
+	PlayerOut string `json:"player_out"`
+	NumberIn  string `json:"number_in,omitempty"`
+	PlayerIn  string `json:"player_in"`
+}
+
+// MatchReport is the parsed IS "zápis o utkání"
+type MatchReport struct {
+	MatchID       string               `json:"match_id"`
+	ReportURL     string               `json:"report_url"`
+	FACRLink      string               `json:"facr_link,omitempty"`
+	Competition   string               `json:"competition,omitempty"`
+	DateTime      string               `json:"date_time,omitempty"`
+	Venue         string               `json:"venue,omitempty"`
+	Score         string               `json:"score,omitempty"`
+	HalfTimeScore string               `json:"half_time_score,omitempty"`
+	Attendance    int                  `json:"attendance,omitempty"`
+	Home          TeamReport           `json:"home"`
+	Away          TeamReport           `json:"away"`
+	Goals         []ReportGoal         `json:"goals,omitempty"`
+	Cards         []ReportCard         `json:"cards,omitempty"`
+	Substitutions []ReportSubstitution `json:"substitutions,omitempty"`
+}
+
+// isMatchReportURL builds the public IS match report URL for a match ID.
+func isMatchReportURL(matchID string) string {
+	return isURL("/public/zapasy/zapis-o-utkani-report.aspx?zapas=%s&zapis=1", neturl.QueryEscape(matchID))
+}
+
+// fetchMatchReport downloads and parses the IS match report of matchID.
+// Reports of finished matches no longer change, so the parsed result is
+// cached for CACHE_TTL_FINISHED_REPORT.
+func fetchMatchReport(ctx context.Context, clubType, matchID string) (*MatchReport, error) {
+	if cached := cachedMatchReport(ctx, clubType, matchID); cached != nil {
+		return cached, nil
+	}
+
+	reportURL := isMatchReportURL(matchID)
+	doc, err := fetchDocument(ctx, reportURL)
+	if err != nil {
+		return nil, err
+	}
+	report := parseMatchReport(doc, clubType)
+	report.MatchID = matchID
+	report.ReportURL = reportURL
+	report.FACRLink = facrMatchURL(clubType, matchID)
+	if reportFinished(report, time.Now()) {
+		storeEntity(reportCacheKey(clubType, matchID), report)
+	}
+	return report, nil
+}
+
+// cachedMatchReport returns the cached report of a finished match, or nil
+// when it has not been parsed yet.
+func cachedMatchReport(ctx context.Context, clubType, matchID string) *MatchReport {
+	var cached MatchReport
+	if cachedEntity(ctx, reportCacheKey(clubType, matchID), activeTTLs.FinishedReport, &cached) {
+		return &cached
+	}
+	return nil
+}
+
+func reportCacheKey(clubType, matchID string) string {
+	return "report:" + clubType + ":" + matchID
+}
+
+// reportFinished reports whether the match of report is over: it has a score
+// and kicked off more than three hours ago.
+func reportFinished(report *MatchReport, now time.Time) bool {
+	kickoff, _, ok := parseKickoff(report.DateTime)
+	return ok && report.Score != "" && kickoff.Add(3*time.Hour).Before(now)
+}
+
+// getMatchReport returns lineups, goals, cards and substitutions of one match
+func getMatchReport(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	matchID := strings.TrimSpace(vars["matchID"])
+	clubType := vars["type"]
+	if matchID == "" {
+		http.Error(w, "Match ID is required", http.StatusBadRequest)
+		return
+	}
+	if clubType != "football" && clubType != "futsal" {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+
+	report, err := fetchMatchReport(r.Context(), clubType, matchID)
+	if err != nil {
+		writeFetchError(w, "match report", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+var (
+	reScoreWithHalf = regexp.MustCompile(`(\d+)\s*:\s*(\d+)(?:\s*\(\s*(\d+)\s*:\s*(\d+)\s*\))?`)
+	reEventMinute   = regexp.MustCompile(`^(\d{1,3})(?:\s*\+\s*(\d{1,2}))?\.?`)
+	reTextEvent     = regexp.MustCompile(`(\d{1,3})(?:\s*\+\s*(\d{1,2}))?\s*\.?\s*(?:min\.?)?\s+([^,;]+)`)
+	reShirtNumber   = regexp.MustCompile(`^\d{1,3}$`)
+	reLeadingNumber = regexp.MustCompile(`^\(?(\d{1,3})\)?\.?\s+`)
+)
+
+// reportField is a "Label: value" pair found anywhere in the report
+type reportField struct {
+	label string // folded (see foldLabel) by collectReportFields, as written by scanFields
+	value string
+}
+
+// parseMatchReport extracts everything we understand from an IS match report.
+// The report is a loosely structured document, so the parser works on labels
+// and table headers rather than fixed positions.
+func parseMatchReport(doc *goquery.Document, clubType string) *MatchReport {
+	report := &MatchReport{}
+
+	var homeCoach, awayCoach []string
+	textEvents := map[string][]reportField{}
+	for _, f := range collectReportFields(doc) {
+		switch {
+		case strings.HasPrefix(f.label, "vysledek") || f.label == "konecny stav":
+			if m := reScoreWithHalf.FindStringSubmatch(f.value); m != nil && report.Score == "" {
+				report.Score = m[1] + ":" + m[2]
+				if m[3] != "" {
+					report.HalfTimeScore = m[3] + ":" + m[4]
+				}
+			}
+		case strings.HasPrefix(f.label, "polocas"):
+			if m := reScoreWithHalf.FindStringSubmatch(f.value); m != nil {
+				report.HalfTimeScore = m[1] + ":" + m[2]
+			}
+		case strings.Contains(f.label, "divak") || strings.Contains(f.label, "navstev"):
+			if n, ok := leadingInt(strings.ReplaceAll(f.value, " ", "")); ok {
+				report.Attendance = n
+			}
+		case strings.HasPrefix(f.label, "trener"):
+			switch labelSide(f.label) {
+			case "home":
+				homeCoach = append(homeCoach, f.value)
+			case "away":
+				awayCoach = append(awayCoach, f.value)
+			default:
+				// Without a side in the label the home coach comes first
+				if len(homeCoach) == 0 {
+					homeCoach = append(homeCoach, f.value)
+				} else {
+					awayCoach = append(awayCoach, f.value)
+				}
+			}
+		case strings.HasPrefix(f.label, "soutez"):
+			report.Competition = f.value
+		case strings.HasPrefix(f.label, "datum") || strings.HasPrefix(f.label, "termin"):
+			report.DateTime = f.value
+		case strings.HasPrefix(f.label, "hriste") || strings.HasPrefix(f.label, "misto"):
+			report.Venue = f.value
+		case f.label == "domaci" || f.label == "domaci druzstvo":
+			report.Home.Name = f.value
+		case f.label == "hoste" || f.label == "hostujici druzstvo":
+			report.Away.Name = f.value
+		default:
+			if kind := eventKind(f.label); kind != "" {
+				textEvents[kind] = append(textEvents[kind], f)
+			}
+		}
+	}
+	if len(homeCoach) > 0 {
+		report.Home.Coach = homeCoach[0]
+	}
+	if len(awayCoach) > 0 {
+		report.Away.Coach = awayCoach[0]
+	}
+	if report.Home.Name == "" || report.Away.Name == "" {
+		// Headline like "FC Home - FC Away"
+		doc.Find("h1, h2").EachWithBreak(func(_ int, h *goquery.Selection) bool {
+			t := collapseSpaces(h.Text())
+			for _, sep := range []string{" – ", " - "} {
+				if home, away, ok := strings.Cut(t, sep); ok {
+					if report.Home.Name == "" {
+						report.Home.Name = strings.TrimSpace(home)
+					}
+					if report.Away.Name == "" {
+						report.Away.Name = strings.TrimSpace(reScoreWithHalf.ReplaceAllString(away, ""))
+					}
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	starterCount := 11
+	if strings.EqualFold(clubType, "futsal") {
+		starterCount = 5
+	}
+	lineups := 0
+	doc.Find("table").Each(func(_ int, table *goquery.Selection) {
+		// Only innermost tables carry rows; layout tables wrap them
+		if table.Find("table").Length() > 0 {
+			return
+		}
+		cols := tableColumns(table)
+		nameCol := columnIndex(cols, func(c string) bool {
+			return strings.Contains(c, "jmeno") || strings.Contains(c, "prijmeni") || strings.HasPrefix(c, "hrac")
+		})
+		minCol := columnIndex(cols, func(c string) bool { return strings.HasPrefix(c, "min") || c == "cas" })
+		title := tableTitle(table)
+		if minCol >= 0 {
+			parseReportEvents(report, table, cols, minCol, title)
+			return
+		}
+		numCol := columnIndex(cols, func(c string) bool {
+			return c == "c" || c == "c." || c == "#" || strings.HasPrefix(c, "cislo") || strings.HasPrefix(c, "dres")
+		})
+		if nameCol < 0 || numCol < 0 {
+			return
+		}
+		side := labelSide(title)
+		if side == "" {
+			side = "home"
+			if lineups > 0 {
+				side = "away"
+			}
+		}
+		lineups++
+		team := &report.Home
+		if side == "away" {
+			team = &report.Away
+		}
+		if team.Name == "" {
+			if _, name, ok := strings.Cut(title, ":"); ok {
+				team.Name = strings.TrimSpace(name)
+			}
+		}
+		parseReportLineup(team, table, cols, numCol, nameCol, starterCount)
+	})
+
+	// Some reports list events as text ("Branky: 12. Novák, 40. Dvořák") instead of tables
+	for _, kind := range []string{"goal", "yellow", "red", "sub"} {
+		if reportHasEvents(report, kind) {
+			continue
+		}
+		for _, f := range textEvents[kind] {
+			// Without "domácí"/"hosté" in the label the side is unknown
+			side := labelSide(f.label)
+			for _, m := range reTextEvent.FindAllStringSubmatch(f.value, -1) {
+				minute, _ := strconv.Atoi(m[1])
+				stoppage, _ := strconv.Atoi(m[2])
+				addReportEvent(report, kind, f.label+" "+m[3], side, minute, stoppage, "", m[3], "", "")
+				if kind == "goal" {
+					ownGoalOfOpponent(report)
+				}
+			}
+		}
+	}
+	return report
+}
+
+// collectReportFields returns the fields of scanFields with folded labels.
+// Labels containing digits are left out: they are scores and times rather
+// than report fields.
+func collectReportFields(doc *goquery.Document) []reportField {
+	var fields []reportField
+	for _, f := range scanFields(doc) {
+		if !strings.ContainsAny(f.label, "0123456789") {
+			fields = append(fields, reportField{label: foldLabel(f.label), value: f.value})
+		}
+	}
+	return fields
+}
+
+// scanFields walks leaf elements in document order and pairs labels with
+// their values, handling "Label: value", "<b>Label:</b> value" and
+// label/value split across neighbouring table cells. Labels are kept as
+// written.
+func scanFields(doc *goquery.Document) []reportField {
+	const leafSel = "td, th, p, li, span, div, strong, b, label, dt, dd"
+	var leaves []*goquery.Selection
+	doc.Find(leafSel).Each(func(_ int, s *goquery.Selection) {
+		if s.Find(leafSel).Length() > 0 {
+			return
+		}
+		if collapseSpaces(s.Text()) != "" {
+			leaves = append(leaves, s)
+		}
+	})
+
+	var fields []reportField
+	for i, s := range leaves {
+		text := collapseSpaces(s.Text())
+		label, value, ok := strings.Cut(text, ":")
+		if !ok {
+			continue
+		}
+		label = strings.TrimSpace(label)
+		value = strings.TrimSpace(value)
+		if label == "" || len([]rune(label)) > 30 {
+			continue
+		}
+		if value == "" {
+			parent := s.Parent()
+			parentText := ""
+			if name := goquery.NodeName(parent); name != "tr" && name != "dl" {
+				parentText = collapseSpaces(parent.Text())
+			}
+			if rest := strings.TrimSpace(strings.TrimPrefix(parentText, text)); rest != "" && rest != parentText {
+				value = rest
+			} else if i+1 < len(leaves) {
+				value = collapseSpaces(leaves[i+1].Text())
+			}
+		}
+		if value == "" {
+			continue
+		}
+		fields = append(fields, reportField{label: label, value: value})
+	}
+	return fields
+}
+
+// tableColumns returns folded header texts of a table (th cells of the first header row).
+func tableColumns(table *goquery.Selection) []string {
+	var cols []string
+	table.Find("tr").EachWithBreak(func(_ int, tr *goquery.Selection) bool {
+		ths := tr.Children().Filter("th")
+		if ths.Length() == 0 {
+			return true
+		}
+		ths.Each(func(_ int, th *goquery.Selection) {
+			cols = append(cols, strings.TrimSuffix(foldLabel(th.Text()), ":"))
+		})
+		return false
+	})
+	return cols
+}
+
+func columnIndex(cols []string, match func(string) bool) int {
+	for i, c := range cols {
+		if match(c) {
+			return i
+		}
+	}
+	return -1
+}
+
+// tableTitle finds the caption or nearest preceding heading of a table.
+func tableTitle(table *goquery.Selection) string {
+	if c := collapseSpaces(table.Find("caption").First().Text()); c != "" {
+		return c
+	}
+	const headings = "h1, h2, h3, h4, h5, h6, .nadpis, .title"
+	for s, depth := table, 0; s.Length() > 0 && depth < 3; s, depth = s.Parent(), depth+1 {
+		if h := s.PrevAllFiltered(headings).First(); h.Length() > 0 {
+			return collapseSpaces(h.Text())
+		}
+	}
+	return ""
+}
+
+// labelSide tells which team a label or heading refers to.
+func labelSide(label string) string {
+	l := foldLabel(label)
+	switch {
+	case strings.Contains(l, "domac"):
+		return "home"
+	case strings.Contains(l, "host"):
+		return "away"
+	}
+	return ""
+}
+
+// hasWord reports whether the folded text contains one of words as a whole token.
+func hasWord(text string, words ...string) bool {
+	tokens := strings.FieldsFunc(foldLabel(text), func(r rune) bool {
+		return r == ' ' || r == '.' || r == ',' || r == '(' || r == ')' || r == '[' || r == ']' || r == '/'
+	})
+	for _, t := range tokens {
+		for _, w := range words {
+			if t == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// eventKind classifies a label, heading or event type into goal, yellow, red or sub.
+func eventKind(label string) string {
+	l := foldLabel(label)
+	has := func(words ...string) bool { return hasWord(l, words...) }
+	switch {
+	case strings.Contains(l, "strid"):
+		return "sub"
+	case has("ck", "cervena") || strings.Contains(l, "vylouc"):
+		return "red"
+	case has("zk", "zluta") || strings.Contains(l, "napomin"):
+		return "yellow"
+	// Whole words only: "Brankář" (goalkeeper) is not a goal
+	case has("branka", "branky", "branek", "gol", "goly", "gy", "vg", "vlastni"):
+		return "goal"
+	}
+	return ""
+}
+
+func reportHasEvents(report *MatchReport, kind string) bool {
+	switch kind {
+	case "goal":
+		return len(report.Goals) > 0
+	case "sub":
+		return len(report.Substitutions) > 0
+	default:
+		card := "yellow"
+		if kind == "red" {
+			card = "red"
+		}
+		for _, c := range report.Cards {
+			if c.Card == card {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseReportLineup reads players of one team. Substitutes follow a
+// "Náhradníci" row or are flagged in their own column; without either the
+// first starterCount players are the starting lineup.
+func parseReportLineup(team *TeamReport, table *goquery.Selection, cols []string, numCol, nameCol, starterCount int) {
+	benchCol := columnIndex(cols, func(c string) bool { return c == "z/n" || strings.HasPrefix(c, "nahrad") || c == "n" })
+	captainCol := columnIndex(cols, func(c string) bool { return c == "k" || strings.HasPrefix(c, "kapit") })
+	keeperCol := columnIndex(cols, func(c string) bool { return c == "b" || strings.HasPrefix(c, "brank") })
+
+	var players []ReportPlayer
+	var bench []bool
+	explicitBench := benchCol >= 0
+	onBench := false
+	table.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		cells := tr.Children().Filter("td")
+		if cells.Length() == 0 {
+			return
+		}
+		cell := func(i int) string {
+			if i < 0 || i >= cells.Length() {
+				return ""
+			}
+			return collapseSpaces(cells.Eq(i).Text())
+		}
+		if cells.Length() <= nameCol {
+			if strings.Contains(foldLabel(tr.Text()), "nahrad") {
+				onBench = true
+				explicitBench = true
+			}
+			return
+		}
+		number := cell(numCol)
+		name := cell(nameCol)
+		if name == "" || (number != "" && !reShirtNumber.MatchString(number)) {
+			return
+		}
+		p := ReportPlayer{Number: number, Name: name}
+		for _, marker := range []string{"(K)", "(C)", "[K]"} {
+			if strings.Contains(p.Name, marker) {
+				p.Captain = true
+				p.Name = strings.TrimSpace(strings.ReplaceAll(p.Name, marker, ""))
+			}
+		}
+		for _, marker := range []string{"(B)", "(G)", "[B]"} {
+			if strings.Contains(p.Name, marker) {
+				p.Goalkeeper = true
+				p.Name = strings.TrimSpace(strings.ReplaceAll(p.Name, marker, ""))
+			}
+		}
+		if captainCol >= 0 && cell(captainCol) != "" {
+			p.Captain = true
+		}
+		if keeperCol >= 0 && cell(keeperCol) != "" {
+			p.Goalkeeper = true
+		}
+		sub := onBench
+		if benchCol >= 0 {
+			v := foldLabel(cell(benchCol))
+			sub = v == "n" || strings.HasPrefix(v, "nahrad") || (cols[benchCol] == "n" && v != "")
+		}
+		players = append(players, p)
+		bench = append(bench, sub)
+	})
+
+	for i, p := range players {
+		sub := bench[i]
+		if !explicitBench {
+			sub = i >= starterCount
+		}
+		if sub {
+			team.Substitutes = append(team.Substitutes, p)
+		} else {
+			team.Starters = append(team.Starters, p)
+		}
+	}
+}
+
+// parseReportEvents reads a table of timed events (goals, cards, substitutions).
+func parseReportEvents(report *MatchReport, table *goquery.Selection, cols []string, minCol int, title string) {
+	typeCol := columnIndex(cols, func(c string) bool {
+		return strings.HasPrefix(c, "typ") || strings.HasPrefix(c, "udalost") || c == "trest"
+	})
+	sideCol := columnIndex(cols, func(c string) bool {
+		return strings.HasPrefix(c, "druzstvo") || strings.HasPrefix(c, "tym") || c == "d/h" || c == "klub"
+	})
+	numCol := columnIndex(cols, func(c string) bool { return c == "c" || c == "c." || c == "#" || strings.HasPrefix(c, "cislo") })
+	nameCol := columnIndex(cols, func(c string) bool {
+		return strings.Contains(c, "jmeno") || strings.Contains(c, "prijmeni") || strings.HasPrefix(c, "hrac") || strings.HasPrefix(c, "strelec")
+	})
+	outCol := columnIndex(cols, func(c string) bool {
+		return strings.HasPrefix(c, "odch") || c == "ven" || strings.HasPrefix(c, "stridany")
+	})
+	inCol := columnIndex(cols, func(c string) bool {
+		return strings.HasPrefix(c, "prich") || strings.HasPrefix(c, "nastup") || strings.HasPrefix(c, "stridajici")
+	})
+	reasonCol := columnIndex(cols, func(c string) bool { return strings.HasPrefix(c, "duvod") || strings.HasPrefix(c, "popis") })
+	titleSide := labelSide(title)
+
+	table.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		cells := tr.Children().Filter("td")
+		if cells.Length() <= minCol {
+			return
+		}
+		cell := func(i int) string {
+			if i < 0 || i >= cells.Length() {
+				return ""
+			}
+			return collapseSpaces(cells.Eq(i).Text())
+		}
+		m := reEventMinute.FindStringSubmatch(cell(minCol))
+		if m == nil {
+			return
+		}
+		minute, _ := strconv.Atoi(m[1])
+		stoppage, _ := strconv.Atoi(m[2])
+
+		kindText := title
+		if typeCol >= 0 {
+			kindText = cell(typeCol) + " " + title
+		}
+		if outCol >= 0 && inCol >= 0 {
+			kindText = "střídání"
+		}
+		kind := eventKind(kindText)
+		if kind == "" {
+			return
+		}
+
+		side := titleSide
+		if sideCol >= 0 {
+			switch v := foldLabel(cell(sideCol)); {
+			case v == "d" || strings.HasPrefix(v, "domac"):
+				side = "home"
+			case v == "h" || strings.HasPrefix(v, "host"):
+				side = "away"
+			case report.Home.Name != "" && containsFold(report.Home.Name, v):
+				side = "home"
+			case report.Away.Name != "" && containsFold(report.Away.Name, v):
+				side = "away"
+			}
+		}
+
+		player := cell(nameCol)
+		number := cell(numCol)
+		if number == "" {
+			if nm := reLeadingNumber.FindStringSubmatch(player); nm != nil {
+				number = nm[1]
+				player = strings.TrimSpace(player[len(nm[0]):])
+			}
+		}
+		addReportEvent(report, kind, kindText, side, minute, stoppage, number, player, cell(outCol), cell(inCol))
+		if kind == "goal" && sideCol < 0 {
+			ownGoalOfOpponent(report)
+		}
+		if (kind == "yellow" || kind == "red") && reasonCol >= 0 {
+			report.Cards[len(report.Cards)-1].Reason = cell(reasonCol)
+		}
+	})
+}
+
+// ownGoalOfOpponent fixes the side of the last goal when it is an own goal
+// taken from a per-team list ("Branky domácí"): such lists hold the goals the
+// team was credited with, so the own goal's player belongs to the other team.
+func ownGoalOfOpponent(report *MatchReport) {
+	if g := &report.Goals[len(report.Goals)-1]; g.OwnGoal {
+		g.Side = otherSide(g.Side)
+	}
+}
+
+func otherSide(side string) string {
+	switch side {
+	case "home":
+		return "away"
+	case "away":
+		return "home"
+	}
+	return ""
+}
+
+// addReportEvent appends one classified event to the report. For
+// substitutions without separate out/in columns the player text is split on
+// "za" ("In za Out") or an arrow ("Out → In").
+func addReportEvent(report *MatchReport, kind, kindText, side string, minute, stoppage int, number, player, playerOut, playerIn string) {
+	switch kind {
+	case "goal":
+		text := kindText + " " + player
+		folded := foldLabel(text)
+		g := ReportGoal{Minute: minute, Stoppage: stoppage, Side: side, Number: number, Player: player}
+		g.Penalty = hasWord(text, "pk", "penalta") || strings.Contains(folded, "pokutov")
+		g.OwnGoal = hasWord(text, "vg") || strings.Contains(folded, "vlastn")
+		for _, marker := range []string{"(PK)", "(pk)", "(VG)", "(vg)", "(vlastní)", "(vlastni)"} {
+			g.Player = strings.TrimSpace(strings.ReplaceAll(g.Player, marker, ""))
+		}
+		report.Goals = append(report.Goals, g)
+	case "yellow", "red":
+		report.Cards = append(report.Cards, ReportCard{Minute: minute, Stoppage: stoppage, Side: side, Number: number, Player: player, Card: kind})
+	case "sub":
+		s := ReportSubstitution{Minute: minute, Stoppage: stoppage, Side: side, PlayerOut: playerOut, PlayerIn: playerIn}
+		if s.PlayerOut == "" && s.PlayerIn == "" {
+			// A number cut off the player text belongs to whoever is named first
+			if in, out, ok := strings.Cut(player, " za "); ok {
+				s.PlayerIn, s.PlayerOut = strings.TrimSpace(in), strings.TrimSpace(out)
+				s.NumberIn = number
+			} else {
+				for _, sep := range []string{"→", "->", " - ", " / "} {
+					if out, in, ok := strings.Cut(player, sep); ok {
+						s.PlayerOut, s.PlayerIn = strings.TrimSpace(out), strings.TrimSpace(in)
+						s.NumberOut = number
+						break
+					}
+				}
+			}
+		}
+		for _, p := range []*string{&s.PlayerOut, &s.PlayerIn} {
+			if nm := reLeadingNumber.FindStringSubmatch(*p); nm != nil {
+				if p == &s.PlayerOut {
+					s.NumberOut = nm[1]
+				} else {
+					s.NumberIn = nm[1]
+				}
+				*p = strings.TrimSpace((*p)[len(nm[0]):])
+			}
+		}
+		if s.PlayerOut != "" || s.PlayerIn != "" {
+			report.Substitutions = append(report.Substitutions, s)
+		}
+	}
+}
+
+// leadingInt parses the digits at the start of s.
+func leadingInt(s string) (int, bool) {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:end])
+	return n, err == nil
+}
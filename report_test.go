@@ -0,0 +1,208 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// parseHTML parses an inline HTML sample for the parser tests.
+func parseHTML(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parse HTML: %v", err)
+	}
+	return doc
+}
+
+const reportTablesHTML = `<html><body>
+<h1>FC Domov – SK Hosté 3:1 (1:0)</h1>
+<table>
+<tr><td>Soutěž:</td><td>Krajský přebor</td></tr>
+<tr><td>Datum:</td><td>12.04.2025 16:30</td></tr>
+<tr><td>Hřiště:</td><td>Stadion Domov</td></tr>
+<tr><td>Výsledek:</td><td>3:1 (1:0)</td></tr>
+<tr><td>Diváků:</td><td>1 250</td></tr>
+<tr><td>Trenér domácí:</td><td>Karel Dlouhý</td></tr>
+<tr><td>Trenér hosté:</td><td>Pavel Krátký</td></tr>
+</table>
+<h3>Sestava domácí: FC Domov</h3>
+<table>
+<tr><th>Č.</th><th>Jméno</th><th>B</th></tr>
+<tr><td>1</td><td>Jan Novák</td><td>x</td></tr>
+<tr><td>7</td><td>Petr Dvořák (K)</td><td></td></tr>
+<tr><td colspan="3">Náhradníci</td></tr>
+<tr><td>12</td><td>Ondřej Malý</td><td></td></tr>
+</table>
+<h3>Sestava hosté: SK Hosté</h3>
+<table>
+<tr><th>Č.</th><th>Jméno</th></tr>
+<tr><td>1</td><td>Tomáš Černý</td></tr>
+<tr><td>9</td><td>Lukáš Bílý</td></tr>
+</table>
+<h3>Události</h3>
+<table>
+<tr><th>Min.</th><th>Typ</th><th>Družstvo</th><th>Hráč</th></tr>
+<tr><td>12.</td><td>Branka</td><td>D</td><td>7 Petr Dvořák</td></tr>
+<tr><td>45+2</td><td>Branka (PK)</td><td>H</td><td>9 Lukáš Bílý</td></tr>
+<tr><td>60.</td><td>ŽK</td><td>H</td><td>1 Tomáš Černý</td></tr>
+<tr><td>70.</td><td>Vlastní branka</td><td>H</td><td>Tomáš Černý</td></tr>
+<tr><td>75.</td><td>Střídání</td><td>D</td><td>12 Ondřej Malý za 7 Petr Dvořák</td></tr>
+</table>
+</body></html>`
+
+func TestParseMatchReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		clubType string
+		check    func(t *testing.T, r *MatchReport)
+	}{
+		{
+			name:     "tables",
+			html:     reportTablesHTML,
+			clubType: "football",
+			check: func(t *testing.T, r *MatchReport) {
+				if r.Score != "3:1" || r.HalfTimeScore != "1:0" {
+					t.Errorf("score = %q (%q), want 3:1 (1:0)", r.Score, r.HalfTimeScore)
+				}
+				if r.Attendance != 1250 || r.Competition != "Krajský přebor" || r.Venue != "Stadion Domov" || r.DateTime != "12.04.2025 16:30" {
+					t.Errorf("header = %d %q %q %q", r.Attendance, r.Competition, r.Venue, r.DateTime)
+				}
+				if r.Home.Coach != "Karel Dlouhý" || r.Away.Coach != "Pavel Krátký" {
+					t.Errorf("coaches = %q, %q", r.Home.Coach, r.Away.Coach)
+				}
+				wantHome := TeamReport{
+					Name:        "FC Domov",
+					Coach:       "Karel Dlouhý",
+					Starters:    []ReportPlayer{{Number: "1", Name: "Jan Novák", Goalkeeper: true}, {Number: "7", Name: "Petr Dvořák", Captain: true}},
+					Substitutes: []ReportPlayer{{Number: "12", Name: "Ondřej Malý"}},
+				}
+				if !reflect.DeepEqual(r.Home, wantHome) {
+					t.Errorf("home = %+v, want %+v", r.Home, wantHome)
+				}
+				if len(r.Away.Starters) != 2 || len(r.Away.Substitutes) != 0 {
+					t.Errorf("away lineup = %+v", r.Away)
+				}
+				wantGoals := []ReportGoal{
+					{Minute: 12, Side: "home", Number: "7", Player: "Petr Dvořák"},
+					{Minute: 45, Stoppage: 2, Side: "away", Number: "9", Player: "Lukáš Bílý", Penalty: true},
+					{Minute: 70, Side: "away", Player: "Tomáš Černý", OwnGoal: true},
+				}
+				if !reflect.DeepEqual(r.Goals, wantGoals) {
+					t.Errorf("goals = %+v, want %+v", r.Goals, wantGoals)
+				}
+				wantCards := []ReportCard{{Minute: 60, Side: "away", Number: "1", Player: "Tomáš Černý", Card: "yellow"}}
+				if !reflect.DeepEqual(r.Cards, wantCards) {
+					t.Errorf("cards = %+v, want %+v", r.Cards, wantCards)
+				}
+				wantSubs := []ReportSubstitution{{Minute: 75, Side: "home", NumberOut: "7", PlayerOut: "Petr Dvořák", NumberIn: "12", PlayerIn: "Ondřej Malý"}}
+				if !reflect.DeepEqual(r.Substitutions, wantSubs) {
+					t.Errorf("substitutions = %+v, want %+v", r.Substitutions, wantSubs)
+				}
+			},
+		},
+		{
+			name: "text events with side labels",
+			html: `<div><b>Branky domácí:</b> 12. Novák, 40. Dvořák</div>
+<div><b>Branky hosté:</b> 88. Bílý</div>
+<div><b>ŽK hosté:</b> 30. Černý</div>`,
+			clubType: "football",
+			check: func(t *testing.T, r *MatchReport) {
+				want := []ReportGoal{
+					{Minute: 12, Side: "home", Player: "Novák"},
+					{Minute: 40, Side: "home", Player: "Dvořák"},
+					{Minute: 88, Side: "away", Player: "Bílý"},
+				}
+				if !reflect.DeepEqual(r.Goals, want) {
+					t.Errorf("goals = %+v, want %+v", r.Goals, want)
+				}
+				if len(r.Cards) != 1 || r.Cards[0].Side != "away" || r.Cards[0].Card != "yellow" {
+					t.Errorf("cards = %+v", r.Cards)
+				}
+			},
+		},
+		{
+			name: "own goal in a per-team list",
+			html: `<div><b>Branky domácí:</b> 12. Novák, 70. Černý (vlastní)</div>
+<div><b>Branky hosté:</b> 88. Bílý</div>`,
+			clubType: "football",
+			check: func(t *testing.T, r *MatchReport) {
+				// Listed with the home goals, scored by an away player
+				want := []ReportGoal{
+					{Minute: 12, Side: "home", Player: "Novák"},
+					{Minute: 70, Side: "away", Player: "Černý", OwnGoal: true},
+					{Minute: 88, Side: "away", Player: "Bílý"},
+				}
+				if !reflect.DeepEqual(r.Goals, want) {
+					t.Errorf("goals = %+v, want %+v", r.Goals, want)
+				}
+			},
+		},
+		{
+			name: "text events without side labels",
+			html: `<div><b>Branky:</b> 12. Novák</div>
+<div><b>Branky:</b> 40. Dvořák</div>`,
+			clubType: "football",
+			check: func(t *testing.T, r *MatchReport) {
+				want := []ReportGoal{{Minute: 12, Player: "Novák"}, {Minute: 40, Player: "Dvořák"}}
+				if !reflect.DeepEqual(r.Goals, want) {
+					t.Errorf("goals = %+v, want %+v (side left empty)", r.Goals, want)
+				}
+			},
+		},
+		{
+			name: "goalkeeper label is not a goal",
+			html: `<div><b>Brankář domácí:</b> 1 Jan Novák</div>
+<div><b>Brankáři hosté:</b> 16 Tomáš Černý</div>`,
+			clubType: "football",
+			check: func(t *testing.T, r *MatchReport) {
+				if len(r.Goals) != 0 {
+					t.Errorf("goals = %+v, want none", r.Goals)
+				}
+			},
+		},
+		{
+			name: "futsal starters",
+			html: `<h3>Domácí: Futsal Domov</h3>
+<table>
+<tr><th>Č.</th><th>Jméno</th></tr>
+<tr><td>1</td><td>A</td></tr><tr><td>2</td><td>B</td></tr><tr><td>3</td><td>C</td></tr>
+<tr><td>4</td><td>D</td></tr><tr><td>5</td><td>E</td></tr><tr><td>6</td><td>F</td></tr>
+</table>`,
+			clubType: "futsal",
+			check: func(t *testing.T, r *MatchReport) {
+				if r.Home.Name != "Futsal Domov" || len(r.Home.Starters) != 5 || len(r.Home.Substitutes) != 1 {
+					t.Errorf("home = %+v, want 5 starters and 1 substitute", r.Home)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, parseMatchReport(parseHTML(t, tt.html), tt.clubType))
+		})
+	}
+}
+
+func TestEventKind(t *testing.T) {
+	tests := map[string]string{
+		"Branka":         "goal",
+		"Branky hostů":   "goal",
+		"Vlastní branka": "goal",
+		"Brankář":        "",
+		"Brankáři":       "",
+		"ŽK":             "yellow",
+		"Červená karta":  "red",
+		"Střídání":       "sub",
+		"Rozhodčí":       "",
+	}
+	for label, want := range tests {
+		if got := eventKind(label); got != want {
+			t.Errorf("eventKind(%q) = %q, want %q", label, got, want)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// errSeasonNotFound is returned when IS has no data for a requested season.
+var errSeasonNotFound = errors.New("season not found")
+
+var (
+	reSeasonParam = regexp.MustCompile(`^(\d{4})(?:\s*[/-]\s*(\d{2}|\d{4}))?$`)
+	reUUID        = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	reCodeCell    = regexp.MustCompile(`^[A-Z0-9]{2,5}$`)
+)
+
+// isUUID reports whether s is exactly one fotbal.cz/IS UUID.
+func isUUID(s string) bool {
+	return len(s) == 36 && reUUID.MatchString(s)
+}
+
+type seasonKey struct{}
+
+// withSeason makes the club and competition scrapers read season ("2024/2025")
+// instead of the current one.
+func withSeason(ctx context.Context, season string) context.Context {
+	return context.WithValue(ctx, seasonKey{}, season)
+}
+
+func seasonFrom(ctx context.Context) string {
+	season, _ := ctx.Value(seasonKey{}).(string)
+	return season
+}
+
+// parseSeason normalises "2024/2025", "2024/25", "2024-2025" or "2024" to
+// "2024/2025".
+func parseSeason(s string) (string, bool) {
+	m := reSeasonParam.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", false
+	}
+	start, _ := strconv.Atoi(m[1])
+	end := start + 1
+	switch len(m[2]) {
+	case 2:
+		if m[2] != fmt.Sprintf("%02d", end%100) {
+			return "", false
+		}
+	case 4:
+		if m[2] != strconv.Itoa(end) {
+			return "", false
+		}
+	}
+	return fmt.Sprintf("%d/%d", start, end), true
+}
+
+// seasonContext returns the request context carrying ?season=, or writes a
+// 400 response when the season is malformed.
+func seasonContext(w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	v := r.URL.Query().Get("season")
+	if v == "" {
+		return r.Context(), true
+	}
+	season, ok := parseSeason(v)
+	if !ok {
+		http.Error(w, "Invalid season. Use e.g. 2024/2025.", http.StatusBadRequest)
+		return nil, false
+	}
+	return withSeason(r.Context(), season), true
+}
+
+// isClubSeasonURL builds the IS club page listing the club's competitions of
+// one season; rocnik is taken to be the year the season starts. That has not
+// been checked against a recorded IS page (the fixture is hand-written), but
+// seasonCompetitions rejects a page that does not show the season, so a wrong
+// guess ends in errSeasonNotFound rather than in the wrong season's data.
+func isClubSeasonURL(clubID, sportParam, season string) string {
+	return isURL("/public/kluby/detail-klubu.aspx?req=%s&sport=%s&rocnik=%s", neturl.QueryEscape(clubID), sportParam, season[:4])
+}
+
+// seasonCompetitions discovers the club's competitions of season on IS.
+// The IDs differ from season to season, so they cannot be derived from the
+// current ones.
+func seasonCompetitions(ctx context.Context, clubType, clubID, season string) ([]Competition, error) {
+	sportParam, ok := sportParamFor(clubType)
+	if !ok {
+		return nil, fmt.Errorf("invalid club type %q", clubType)
+	}
+	doc, err := fetchDocument(ctx, isClubSeasonURL(clubID, sportParam, season))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPageSeason(doc, season); err != nil {
+		return nil, fmt.Errorf("club %s: %w", clubID, err)
+	}
+	competitions := parseSeasonCompetitions(doc, clubType)
+	if len(competitions) == 0 {
+		return nil, fmt.Errorf("%w: no competitions of club %s in %s", errSeasonNotFound, clubID, season)
+	}
+	return competitions, nil
+}
+
+// checkPageSeason returns errSeasonNotFound unless doc shows season. IS falls
+// back to the current season for seasons it does not know, and a page
+// without any season marker cannot be told apart from that fallback.
+func checkPageSeason(doc *goquery.Document, season string) error {
+	switch shown := pageSeason(doc); shown {
+	case season:
+		return nil
+	case "":
+		return fmt.Errorf("%w: IS page shows no season, wanted %s", errSeasonNotFound, season)
+	default:
+		return fmt.Errorf("%w: IS shows %s instead of %s", errSeasonNotFound, shown, season)
+	}
+}
+
+// parseSeasonCompetitions reads the competition rows of an IS club page: a
+// link to the competition detail or table page, the code in its own cell or
+// in parentheses after the name, and the number of teams.
+func parseSeasonCompetitions(doc *goquery.Document, clubType string) []Competition {
+	var competitions []Competition
+	seen := map[string]bool{}
+	doc.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		link := tr.Find(`a[href*="detail-souteze.aspx"], a[href*="tabulky-souteze.aspx"]`).First()
+		compID := reUUID.FindString(link.AttrOr("href", ""))
+		if compID == "" || seen[compID] {
+			return
+		}
+		seen[compID] = true
+		comp := Competition{ID: compID, Name: collapseSpaces(link.Text()), MatchesLink: fotbalCompetitionURL(clubType, compID)}
+		if m := reCompetitionCode.FindStringSubmatch(comp.Name); m != nil {
+			comp.Code = m[1]
+			comp.Name = collapseSpaces(strings.Replace(comp.Name, m[0], "", 1))
+		}
+		tr.Find("td").Each(func(_ int, td *goquery.Selection) {
+			text := collapseSpaces(td.Text())
+			switch {
+			case td.Find("a").Length() > 0 || text == "":
+			case comp.Code == "" && reCodeCell.MatchString(text) && !isDigits(text):
+				comp.Code = text
+			case isDigits(text):
+				comp.TeamCount = text
+			}
+		})
+		competitions = append(competitions, comp)
+	})
+	return competitions
+}
+
+func isDigits(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// seasonCompetitionID finds the ID competition compID has in season, from
+// the season switch ("Ročník") of its IS table page.
+func seasonCompetitionID(ctx context.Context, clubType, compID, season string) (string, error) {
+	sportParam, ok := sportParamFor(clubType)
+	if !ok {
+		return "", fmt.Errorf("invalid club type %q", clubType)
+	}
+	doc, err := fetchDocument(ctx, isCompetitionTableURL(compID, sportParam))
+	if err != nil {
+		return "", err
+	}
+	if pageSeason(doc) == season {
+		return compID, nil
+	}
+	found := ""
+	doc.Find("option, a").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		m := reSeasonText.FindStringSubmatch(s.Text())
+		if m == nil {
+			return true
+		}
+		if text, _ := parseSeason(m[1] + "/" + m[2]); text != season {
+			return true
+		}
+		found = reUUID.FindString(s.AttrOr("value", "") + " " + s.AttrOr("href", ""))
+		return found == ""
+	})
+	if found == "" {
+		return "", fmt.Errorf("%w: competition %s has no season %s on IS", errSeasonNotFound, compID, season)
+	}
+	return found, nil
+}
+
+// pageSeason returns the season an IS page shows: the selected entry of its
+// season switch, else the season in the page heading.
+func pageSeason(doc *goquery.Document) string {
+	for _, sel := range []string{"option[selected]", "h1, h2, title"} {
+		season := ""
+		doc.Find(sel).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+			if m := reSeasonText.FindStringSubmatch(s.Text()); m != nil {
+				season, _ = parseSeason(m[1] + "/" + m[2])
+			}
+			return season == ""
+		})
+		if season != "" {
+			return season
+		}
+	}
+	return ""
+}
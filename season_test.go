@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSeason(t *testing.T) {
+	tests := map[string]string{
+		"2024/2025":    "2024/2025",
+		"2024/25":      "2024/2025",
+		"2024-2025":    "2024/2025",
+		" 2024 / 25 ":  "2024/2025",
+		"2024":         "2024/2025",
+		"1999/00":      "1999/2000",
+		"2024/2026":    "",
+		"2024/26":      "",
+		"24/25":        "",
+		"2024/2025/26": "",
+		"sezona 2024":  "",
+		"":             "",
+	}
+	for in, want := range tests {
+		got, ok := parseSeason(in)
+		if got != want || ok != (want != "") {
+			t.Errorf("parseSeason(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+}
+
+func TestParseSeasonCompetitions(t *testing.T) {
+	const (
+		liga  = "5b0c2d1e-7a3f-4c88-9d61-2f4e8a1b9c07"
+		pohar = "f49e63bd-55d9-4c5e-93f7-8e482262b88f"
+		club  = "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+	)
+	doc := parseHTML(t, `<table>
+<tr><th>Soutěž</th><th>Kód</th><th>Týmů</th></tr>
+<tr><td><a href="/public/souteze/detail-souteze.aspx?req=`+liga+`&sport=futsal">2. Futsal liga - východ</a></td><td>F2B</td><td>12</td></tr>
+<tr><td><a href="/public/souteze/tabulky-souteze.aspx?req=`+pohar+`">Pohár FAČR (POH)</a></td><td></td><td>32</td></tr>
+<tr><td><a href="/public/souteze/detail-souteze.aspx?req=`+liga+`">2. Futsal liga - východ</a></td><td>F2B</td><td>12</td></tr>
+<tr><td><a href="/public/kluby/detail-klubu.aspx?req=`+club+`">Not a competition</a></td></tr>
+</table>`)
+	want := []Competition{
+		{ID: liga, Code: "F2B", Name: "2. Futsal liga - východ", TeamCount: "12", MatchesLink: fotbalCompetitionURL("futsal", liga)},
+		{ID: pohar, Code: "POH", Name: "Pohár FAČR", TeamCount: "32", MatchesLink: fotbalCompetitionURL("futsal", pohar)},
+	}
+	if got := parseSeasonCompetitions(doc, "futsal"); !reflect.DeepEqual(got, want) {
+		t.Errorf("competitions =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestPageSeason(t *testing.T) {
+	tests := []struct {
+		name, html, want string
+	}{
+		{"selected option", `<h1>Soutěže 2025/2026</h1><select><option>2025/2026</option><option selected>2024/2025</option></select>`, "2024/2025"},
+		{"short form", `<select><option selected value="x">Ročník 2023/24</option></select>`, "2023/2024"},
+		{"heading", `<title>Klub</title><h2>Soutěže klubu 2025/2026</h2><select><option>2024/2025</option></select>`, "2025/2026"},
+		{"none", `<h1>FC Bizoni Uherské Hradiště</h1>`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageSeason(parseHTML(t, tt.html)); got != tt.want {
+				t.Errorf("pageSeason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPageSeason(t *testing.T) {
+	tests := []struct {
+		name, html string
+		ok         bool
+	}{
+		{"requested season", `<select><option>2025/2026</option><option selected>2024/2025</option></select>`, true},
+		{"current season instead", `<h2>Soutěže klubu 2025/2026</h2>`, false},
+		{"no season marker", `<h1>FC Bizoni Uherské Hradiště</h1>`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPageSeason(parseHTML(t, tt.html), "2024/2025")
+			if tt.ok != (err == nil) || (err != nil && !errors.Is(err, errSeasonNotFound)) {
+				t.Errorf("checkPageSeason = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSeasonCompetitionsFromFixtures(t *testing.T) {
+	comps, err := seasonCompetitions(context.Background(), "futsal", bizoniID, "2025/2026")
+	if err != nil || len(comps) == 0 {
+		t.Fatalf("seasonCompetitions = %+v, %v", comps, err)
+	}
+}
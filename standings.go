@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TableRowV2 is a standings row with numeric columns and the score split
+// into goals for/against.
+type TableRowV2 struct {
+	Rank           int       `json:"rank"`
+	Team           string    `json:"team"`
+	TeamID         string    `json:"team_id,omitempty"`
+	TeamLogoURL    string    `json:"team_logo_url,omitempty"`
+	Played         int       `json:"played"`
+	Wins           int       `json:"wins"`
+	Draws          int       `json:"draws"`
+	Losses         int       `json:"losses"`
+	GoalsFor       int       `json:"goals_for"`
+	GoalsAgainst   int       `json:"goals_against"`
+	GoalDifference int       `json:"goal_difference"`
+	Points         int       `json:"points"`
+	Form           *TeamForm `json:"form,omitempty"`
+}
+
+// TableSection is a standings section other than overall, home and away,
+// e.g. a spring-only table. Key is derived from the title ("jaro").
+type TableSection struct {
+	Key    string       `json:"key"`
+	Title  string       `json:"title"`
+	Rows   []TableRow   `json:"rows"`
+	RowsV2 []TableRowV2 `json:"rows_v2,omitempty"`
+}
+
+// TableParseError reports a standings cell that could not be read as a number.
+// The row is still returned with that column left at zero.
+type TableParseError struct {
+	Section string `json:"section,omitempty"`
+	Rank    string `json:"rank"`
+	Team    string `json:"team"`
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	Error   string `json:"error"`
+}
+
+// tableRowV2 converts a scraped row into its typed form.
+func tableRowV2(row TableRow) (TableRowV2, []TableParseError) {
+	v2 := TableRowV2{Team: row.Team, TeamID: row.TeamID, TeamLogoURL: row.TeamLogoURL, Form: row.Form}
+	var errs []TableParseError
+	fail := func(field, value string, err error) {
+		errs = append(errs, TableParseError{Rank: row.Rank, Team: row.Team, Field: field, Value: value, Error: err.Error()})
+	}
+	for _, col := range []struct {
+		field string
+		value string
+		dst   *int
+	}{
+		// IS writes ranks as "1." and shared ranks as "3.-4."; the first number is the rank
+		{"rank", strings.SplitN(strings.TrimSpace(row.Rank), "-", 2)[0], &v2.Rank},
+		{"played", row.Played, &v2.Played},
+		{"wins", row.Wins, &v2.Wins},
+		{"draws", row.Draws, &v2.Draws},
+		{"losses", row.Losses, &v2.Losses},
+		{"points", row.Points, &v2.Points},
+	} {
+		n, err := parseTableInt(col.value)
+		if err != nil {
+			fail(col.field, col.value, err)
+			continue
+		}
+		*col.dst = n
+	}
+
+	goalsFor, goalsAgainst, ok := strings.Cut(row.Score, ":")
+	if !ok {
+		fail("score", row.Score, fmt.Errorf("expected goals as \"for:against\""))
+		return v2, errs
+	}
+	gf, errFor := parseTableInt(goalsFor)
+	ga, errAgainst := parseTableInt(goalsAgainst)
+	if errFor != nil || errAgainst != nil {
+		fail("score", row.Score, fmt.Errorf("expected goals as \"for:against\""))
+		return v2, errs
+	}
+	v2.GoalsFor, v2.GoalsAgainst, v2.GoalDifference = gf, ga, gf-ga
+	return v2, errs
+}
+
+// parseTableInt parses one numeric standings cell ("12", "1.", " 7 ").
+func parseTableInt(s string) (int, error) {
+	s = strings.TrimSuffix(collapseSpaces(s), ".")
+	if s == "" {
+		return 0, fmt.Errorf("empty value")
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(s, "−", "-"))
+	if err != nil {
+		return 0, fmt.Errorf("not a number")
+	}
+	return n, nil
+}
+
+// withTableV2 adds the typed rows (and any conversion errors) to table.
+func withTableV2(table *CompetitionTable) {
+	if table == nil {
+		return
+	}
+	table.ParseErrors = nil
+	convert := func(section string, rows []TableRow) []TableRowV2 {
+		if rows == nil {
+			return nil
+		}
+		out := make([]TableRowV2, 0, len(rows))
+		for _, row := range rows {
+			v2, errs := tableRowV2(row)
+			out = append(out, v2)
+			for _, e := range errs {
+				if section != "overall" {
+					e.Section = section
+				}
+				table.ParseErrors = append(table.ParseErrors, e)
+			}
+		}
+		return out
+	}
+	table.OverallV2 = convert("overall", table.Overall)
+	table.HomeV2 = convert("home", table.Home)
+	table.AwayV2 = convert("away", table.Away)
+	for i := range table.Other {
+		table.Other[i].RowsV2 = convert(table.Other[i].Key, table.Other[i].Rows)
+	}
+}
+
+// tableOptions selects what the standings endpoints return.
+type tableOptions struct {
+	V2       bool          // add typed rows
+	Sections tableSections // nil means every section
+	Form     int           // number of recent results per team, 0 for none
+}
+
+// tableOptionsFromRequest reads ?v=2, ?sections= and ?include=form, or
+// writes a 400 response when form_count is malformed.
+func tableOptionsFromRequest(w http.ResponseWriter, r *http.Request) (tableOptions, bool) {
+	form, ok := formCountFromRequest(r)
+	if !ok {
+		http.Error(w, "form_count must be a positive number", http.StatusBadRequest)
+		return tableOptions{}, false
+	}
+	return tableOptions{
+		V2:       r.URL.Query().Get("v") == "2",
+		Sections: parseTableSections(r.URL.Query().Get("sections")),
+		Form:     form,
+	}, true
+}
+
+// tableSections is a set of section keys: "overall", "home", "away", "other"
+// (every further section) or the key of one further section.
+type tableSections map[string]bool
+
+// parseTableSections reads a comma separated section list; "" and "all"
+// select everything.
+func parseTableSections(s string) tableSections {
+	sections := tableSections{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "all" {
+			return nil
+		}
+		if part != "" {
+			sections[part] = true
+		}
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	return sections
+}
+
+func (s tableSections) wants(key string) bool {
+	if s == nil || s[key] {
+		return true
+	}
+	switch key {
+	case "overall", "home", "away":
+		return false
+	}
+	return s["other"]
+}
+
+// tableSectionKey maps an IS section heading to its key: "overall" for
+// "Tabulka celková", "home" for "Tabulka domácí", "away" for "Tabulka
+// venkovní" and a slug of the remaining words otherwise.
+func tableSectionKey(title string) string {
+	l := foldLabel(title)
+	switch {
+	case strings.Contains(l, "celkov"):
+		return "overall"
+	case strings.Contains(l, "domac") || hasWord(l, "doma"):
+		return "home"
+	case strings.Contains(l, "venk") || hasWord(l, "venku"):
+		return "away"
+	}
+	l = strings.TrimSpace(strings.TrimPrefix(l, "tabulka"))
+	key := strings.Join(strings.FieldsFunc(l, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "-")
+	if key == "" {
+		key = "table"
+	}
+	return key
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTableRowV2(t *testing.T) {
+	tests := []struct {
+		name   string
+		row    TableRow
+		want   TableRowV2
+		fields []string // fields reported as parse errors
+	}{
+		{
+			name: "plain row",
+			row:  TableRow{Rank: "1.", Team: "FC Domov", Played: "10", Wins: "8", Draws: "1", Losses: "1", Score: "30:12", Points: "25"},
+			want: TableRowV2{Rank: 1, Team: "FC Domov", Played: 10, Wins: 8, Draws: 1, Losses: 1, GoalsFor: 30, GoalsAgainst: 12, GoalDifference: 18, Points: 25},
+		},
+		{
+			name: "shared rank and negative points",
+			row:  TableRow{Rank: "3.-4.", Team: "SK Hosté", Played: " 10 ", Wins: "0", Draws: "0", Losses: "10", Score: "5:40", Points: "−3"},
+			want: TableRowV2{Rank: 3, Team: "SK Hosté", Played: 10, Losses: 10, GoalsFor: 5, GoalsAgainst: 40, GoalDifference: -35, Points: -3},
+		},
+		{
+			name:   "unreadable cells",
+			row:    TableRow{Rank: "1.", Team: "FC Domov", Played: "", Wins: "x", Draws: "1", Losses: "1", Score: "30-12", Points: "25"},
+			want:   TableRowV2{Rank: 1, Team: "FC Domov", Draws: 1, Losses: 1, Points: 25},
+			fields: []string{"played", "wins", "score"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := tableRowV2(tt.row)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("row = %+v, want %+v", got, tt.want)
+			}
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("parse errors = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestTableSections(t *testing.T) {
+	keys := map[string]string{
+		"Tabulka celková":       "overall",
+		"Tabulka domácí":        "home",
+		"Tabulka venkovní":      "away",
+		"Tabulka jaro":          "jaro",
+		"Tabulka - 1. polovina": "1-polovina",
+		"Tabulka":               "table",
+	}
+	for title, want := range keys {
+		if got := tableSectionKey(title); got != want {
+			t.Errorf("tableSectionKey(%q) = %q, want %q", title, got, want)
+		}
+	}
+
+	if parseTableSections("") != nil || parseTableSections("overall,all") != nil {
+		t.Error(`"" and "all" should select every section`)
+	}
+	s := parseTableSections(" Overall , other")
+	for key, want := range map[string]bool{"overall": true, "home": false, "away": false, "jaro": true} {
+		if got := s.wants(key); got != want {
+			t.Errorf("wants(%q) = %v, want %v", key, got, want)
+		}
+	}
+	if s := parseTableSections("jaro"); s.wants("podzim") || !s.wants("jaro") {
+		t.Error(`"jaro" should select only that further section`)
+	}
+}
+
+const tablePageHTML = `<html><body>
+<h3>Tabulka celková</h3>
+<div class="list tabulky"><table class="vysledky-tabulky"><tbody>
+<tr><th>#</th><th>Tým</th><th>Z</th><th>V</th><th>R</th><th>P</th><th>Skóre</th><th>B</th></tr>
+<tr><td>1.</td><td><a href="/public/kluby/detail-klubu.aspx?req=202216d4-f045-4786-bd21-dd0c9fe34650">FC Domov</a></td><td>2</td><td>2</td><td>0</td><td>0</td><td>5 : 1</td><td>6</td></tr>
+<tr><td>2.</td><td><a href="/public/kluby/detail-klubu.aspx?req=441d3783-06aa-436a-b438-359300ee0371">SK Hosté</a></td><td>2</td><td>0</td><td>0</td><td>2</td><td>1 : 5</td><td>0</td></tr>
+</tbody></table></div>
+<h3>Tabulka domácí</h3>
+<div class="list tabulky"><table class="vysledky-tabulky"><tbody>
+<tr><td>1.</td><td><a href="/public/kluby/detail-klubu.aspx?req=202216d4-f045-4786-bd21-dd0c9fe34650">FC Domov</a></td><td>1</td><td>1</td><td>0</td><td>0</td><td>3:0</td><td>3</td></tr>
+</tbody></table></div>
+<h3>Tabulka jaro</h3>
+<div class="list tabulky"><table class="vysledky-tabulky"><tbody>
+<tr><td>1.</td><td><a href="/public/kluby/detail-klubu.aspx?req=441d3783-06aa-436a-b438-359300ee0371">SK Hosté</a></td><td>0</td><td>0</td><td>0</td><td>0</td><td>0:0</td><td>0</td></tr>
+</tbody></table></div>
+<h3>Tabulka jaro</h3>
+<div class="list tabulky"><table class="vysledky-tabulky"><tbody></tbody></table></div>
+</body></html>`
+
+func TestParseCompetitionTable(t *testing.T) {
+	doc := parseHTML(t, tablePageHTML)
+
+	table := parseCompetitionTable(context.Background(), doc, nil)
+	if len(table.Overall) != 2 || len(table.Home) != 1 || table.Away != nil || len(table.Other) != 1 {
+		t.Fatalf("sections = %d overall, %d home, %v away, %d other", len(table.Overall), len(table.Home), table.Away, len(table.Other))
+	}
+	first := table.Overall[0]
+	if first.Rank != "1." || first.Team != "FC Domov" || first.TeamID != "202216d4-f045-4786-bd21-dd0c9fe34650" || first.Score != "5:1" || first.Points != "6" {
+		t.Errorf("first row = %+v", first)
+	}
+	if other := table.Other[0]; other.Key != "jaro" || other.Title != "Tabulka jaro" || len(other.Rows) != 1 {
+		t.Errorf("other section = %+v", other)
+	}
+
+	table = parseCompetitionTable(context.Background(), doc, parseTableSections("home"))
+	if table.Overall != nil || len(table.Home) != 1 || table.Other != nil {
+		t.Errorf("?sections=home returned %+v", table)
+	}
+
+	withTableV2(table)
+	if table.OverallV2 != nil || len(table.HomeV2) != 1 || table.HomeV2[0].GoalDifference != 3 {
+		t.Errorf("v2 = %+v", table)
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// PlayerStats are the season numbers of one player, summed over match reports.
+type PlayerStats struct {
+	Name         string   `json:"name"`
+	Team         string   `json:"team,omitempty"`
+	TeamID       string   `json:"team_id,omitempty"`
+	Numbers      []string `json:"numbers,omitempty"`
+	Appearances  int      `json:"appearances"`
+	Starts       int      `json:"starts"`
+	Minutes      int      `json:"minutes,omitempty"` // football only, futsal has rolling substitutions
+	Goals        int      `json:"goals"`
+	PenaltyGoals int      `json:"penalty_goals,omitempty"`
+	OwnGoals     int      `json:"own_goals,omitempty"`
+	YellowCards  int      `json:"yellow_cards"`
+	RedCards     int      `json:"red_cards"`
+}
+
+// ClubPlayerStats is the response of the club player statistics endpoint.
+type ClubPlayerStats struct {
+	ClubID       string             `json:"club_id"`
+	ClubType     string             `json:"club_type"`
+	Name         string             `json:"name"`
+	Competitions []StatsCompetition `json:"competitions"`
+	Players      []PlayerStats      `json:"players"`
+}
+
+// CompetitionScorers is the response of the competition scorers endpoint.
+type CompetitionScorers struct {
+	StatsCompetition
+	Type    string        `json:"type"`
+	Season  string        `json:"season,omitempty"`
+	Players []PlayerStats `json:"players"`
+}
+
+// StatsCompetition tells which reports the statistics are built from.
+type StatsCompetition struct {
+	ID            string `json:"id"`
+	Code          string `json:"code,omitempty"`
+	Name          string `json:"name"`
+	Reports       int    `json:"reports"`
+	FailedReports int    `json:"failed_reports,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+func statsCompetition(comp competitionReports) StatsCompetition {
+	return StatsCompetition{
+		ID:            comp.Competition.ID,
+		Code:          comp.Competition.Code,
+		Name:          comp.Competition.Name,
+		Reports:       len(comp.Reports),
+		FailedReports: comp.Failed,
+		Error:         comp.Competition.Error,
+	}
+}
+
+// getClubPlayerStats returns season statistics of the club's players
+func getClubPlayerStats(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clubID := vars["id"]
+	clubType := vars["type"]
+	if clubID == "" {
+		http.Error(w, "Club ID is required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := sportParamFor(clubType); !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+
+	club, comps, err := collectClubReports(r.Context(), clubType, clubID, competitionFilterFromRequest(r), time.Now())
+	if err != nil {
+		writeFetchError(w, "club data", err)
+		return
+	}
+	stats := &ClubPlayerStats{ClubID: clubID, ClubType: clubType, Name: club.Name, Competitions: []StatsCompetition{}}
+	acc := newStatsAccumulator(clubType)
+	for _, comp := range comps {
+		stats.Competitions = append(stats.Competitions, statsCompetition(comp))
+		for _, pr := range comp.Reports {
+			// All teams of the club are summed, so players are keyed by name only
+			acc.addMatch(pr.Report, pr.Side, func(string) (string, string) { return club.Name, clubID })
+		}
+	}
+	stats.Players = acc.sorted(false)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// getCompetitionScorers returns the scorers (or with ?all=1 every player) of a competition
+func getCompetitionScorers(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	compID := strings.TrimSpace(vars["competitionID"])
+	clubType := vars["type"]
+	if compID == "" {
+		http.Error(w, "Competition ID is required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := sportParamFor(clubType); !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+
+	info, err := scrapeCompetition(r.Context(), clubType, compID, tableOptions{})
+	if err != nil {
+		writeFetchError(w, "competition table", err)
+		return
+	}
+	now := time.Now()
+	comps := []competitionReports{{Competition: info.Competition}}
+	for _, m := range info.Matches {
+		if m.MatchID != "" && matchPlayed(m, now) && m.Status != StatusForfeited {
+			comps[0].Reports = append(comps[0].Reports, playedReport{Match: m})
+		}
+	}
+	fetchPlayedReports(r.Context(), clubType, comps)
+
+	acc := newStatsAccumulator(clubType)
+	for _, pr := range comps[0].Reports {
+		m := pr.Match
+		team := func(side string) (string, string) {
+			if side == "away" {
+				return m.Away, m.AwayID
+			}
+			return m.Home, m.HomeID
+		}
+		acc.addMatch(pr.Report, "home", team)
+		acc.addMatch(pr.Report, "away", team)
+	}
+	scorers := &CompetitionScorers{
+		StatsCompetition: statsCompetition(comps[0]),
+		Type:             clubType,
+		Season:           info.Season,
+		Players:          acc.sorted(!queryFlag(r, "all")),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scorers)
+}
+
+// statsAccumulator sums player statistics over match reports, keyed by team
+// and folded player name.
+type statsAccumulator struct {
+	duration int  // regular playing time in minutes
+	rolling  bool // futsal: substitutions are not recorded, minutes are unknown
+	players  map[string]*PlayerStats
+	order    []string
+}
+
+func newStatsAccumulator(clubType string) *statsAccumulator {
+	acc := &statsAccumulator{duration: 90, players: map[string]*PlayerStats{}}
+	if strings.EqualFold(clubType, "futsal") {
+		acc.duration, acc.rolling = 40, true
+	}
+	return acc
+}
+
+func (acc *statsAccumulator) player(team, teamID, name string) *PlayerStats {
+	key := strings.ToLower(teamID) + "|" + foldLabel(team) + "|" + foldLabel(name)
+	if teamID != "" {
+		key = strings.ToLower(teamID) + "|" + foldLabel(name)
+	}
+	p, ok := acc.players[key]
+	if !ok {
+		p = &PlayerStats{Name: name, Team: team, TeamID: teamID}
+		acc.players[key] = p
+		acc.order = append(acc.order, key)
+	}
+	return p
+}
+
+// addMatch adds the lineup and events of one side of report. team names the
+// team of a side.
+func (acc *statsAccumulator) addMatch(report *MatchReport, side string, team func(side string) (string, string)) {
+	teamName, teamID := team(side)
+	lineup := report.Home
+	if side == "away" {
+		lineup = report.Away
+	}
+	if teamName == "" {
+		teamName = lineup.Name
+	}
+	var entries []*statsEntry
+	for i, p := range append(append([]ReportPlayer{}, lineup.Starters...), lineup.Substitutes...) {
+		e := &statsEntry{ReportPlayer: p, stats: acc.player(teamName, teamID, p.Name), on: -1, off: acc.duration}
+		if i < len(lineup.Starters) {
+			e.on = 0
+			e.stats.Starts++
+		}
+		if p.Number != "" && !slices.Contains(e.stats.Numbers, p.Number) {
+			e.stats.Numbers = append(e.stats.Numbers, p.Number)
+		}
+		entries = append(entries, e)
+	}
+	find := func(number, name string) *statsEntry {
+		for _, e := range entries {
+			if samePlayer(e.ReportPlayer, number, name) {
+				return e
+			}
+		}
+		return nil
+	}
+	minute := func(m int) int { return min(max(m, 0), acc.duration) }
+
+	for _, s := range report.Substitutions {
+		if s.Side != side {
+			continue
+		}
+		if e := find(s.NumberIn, s.PlayerIn); e != nil && e.on < 0 {
+			e.on = minute(s.Minute)
+		}
+		if e := find(s.NumberOut, s.PlayerOut); e != nil {
+			e.off = min(e.off, minute(s.Minute))
+		}
+	}
+	for _, g := range report.Goals {
+		// Side is the scorer's team, own goals included
+		if g.Side != side {
+			continue
+		}
+		stats := acc.eventPlayer(find(g.Number, g.Player), teamName, teamID, g.Player)
+		switch {
+		case stats == nil:
+		case g.OwnGoal:
+			stats.OwnGoals++
+		default:
+			stats.Goals++
+			if g.Penalty {
+				stats.PenaltyGoals++
+			}
+		}
+	}
+	for _, c := range report.Cards {
+		if c.Side != side {
+			continue
+		}
+		e := find(c.Number, c.Player)
+		stats := acc.eventPlayer(e, teamName, teamID, c.Player)
+		if stats == nil {
+			continue
+		}
+		if c.Card == "red" {
+			stats.RedCards++
+			if e != nil {
+				e.off = min(e.off, minute(c.Minute))
+			}
+		} else {
+			stats.YellowCards++
+		}
+	}
+
+	for _, e := range entries {
+		if acc.rolling {
+			// Everyone listed may have played
+			e.stats.Appearances++
+			continue
+		}
+		if e.on >= 0 {
+			e.stats.Appearances++
+			e.stats.Minutes += max(e.off-e.on, 0)
+		}
+	}
+}
+
+// statsEntry is a lineup player within one match.
+type statsEntry struct {
+	ReportPlayer
+	stats   *PlayerStats
+	on, off int // minutes on the pitch; on < 0 for unused substitutes
+}
+
+// eventPlayer returns the stats of an event's player: those of the lineup
+// entry e when found, else by name (reports without lineups still list scorers).
+func (acc *statsAccumulator) eventPlayer(e *statsEntry, team, teamID, name string) *PlayerStats {
+	if e != nil {
+		return e.stats
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil
+	}
+	log.Printf("stats: %q of %s not in the lineup", name, team)
+	return acc.player(team, teamID, name)
+}
+
+// sorted returns the players by goals, then appearances and name;
+// scorersOnly drops players without a goal.
+func (acc *statsAccumulator) sorted(scorersOnly bool) []PlayerStats {
+	players := []PlayerStats{}
+	for _, key := range acc.order {
+		if p := acc.players[key]; !scorersOnly || p.Goals > 0 {
+			players = append(players, *p)
+		}
+	}
+	sort.SliceStable(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+		if a.Goals != b.Goals {
+			return a.Goals > b.Goals
+		}
+		if a.Appearances != b.Appearances {
+			return a.Appearances > b.Appearances
+		}
+		return a.Name < b.Name
+	})
+	return players
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStatsAccumulator(t *testing.T) {
+	team := func(side string) (string, string) {
+		if side == "away" {
+			return "SK Hosté", "away-id"
+		}
+		return "FC Domov", "home-id"
+	}
+	home := func(name, number string) PlayerStats {
+		return PlayerStats{Name: name, Team: "FC Domov", TeamID: "home-id", Numbers: []string{number}}
+	}
+	away := func(name, number string) PlayerStats {
+		return PlayerStats{Name: name, Team: "SK Hosté", TeamID: "away-id", Numbers: []string{number}}
+	}
+	with := func(p PlayerStats, edit func(*PlayerStats)) PlayerStats {
+		edit(&p)
+		return p
+	}
+	redCard := strings.Replace(reportTablesHTML, "<td>ŽK</td>", "<td>ČK</td>", 1)
+
+	tests := []struct {
+		name     string
+		html     string
+		clubType string
+		want     []PlayerStats
+	}{
+		{
+			name:     "football minutes, goals, own goal and card",
+			html:     reportTablesHTML,
+			clubType: "football",
+			want: []PlayerStats{
+				with(home("Jan Novák", "1"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Minutes = 1, 1, 90 }),
+				with(home("Petr Dvořák", "7"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Minutes, p.Goals = 1, 1, 75, 1 }),
+				with(home("Ondřej Malý", "12"), func(p *PlayerStats) { p.Appearances, p.Minutes = 1, 15 }),
+				// The away keeper's own goal stays with the away team
+				with(away("Tomáš Černý", "1"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Minutes, p.OwnGoals, p.YellowCards = 1, 1, 90, 1, 1 }),
+				with(away("Lukáš Bílý", "9"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Minutes, p.Goals, p.PenaltyGoals = 1, 1, 90, 1, 1 }),
+			},
+		},
+		{
+			name:     "red card ends the minutes",
+			html:     redCard,
+			clubType: "football",
+			want: []PlayerStats{
+				with(home("Jan Novák", "1"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Minutes = 1, 1, 90 }),
+				with(home("Petr Dvořák", "7"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Minutes, p.Goals = 1, 1, 75, 1 }),
+				with(home("Ondřej Malý", "12"), func(p *PlayerStats) { p.Appearances, p.Minutes = 1, 15 }),
+				with(away("Tomáš Černý", "1"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Minutes, p.OwnGoals, p.RedCards = 1, 1, 60, 1, 1 }),
+				with(away("Lukáš Bílý", "9"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Minutes, p.Goals, p.PenaltyGoals = 1, 1, 90, 1, 1 }),
+			},
+		},
+		{
+			name:     "futsal counts every listed player without minutes",
+			html:     reportTablesHTML,
+			clubType: "futsal",
+			want: []PlayerStats{
+				with(home("Jan Novák", "1"), func(p *PlayerStats) { p.Appearances, p.Starts = 1, 1 }),
+				with(home("Petr Dvořák", "7"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Goals = 1, 1, 1 }),
+				with(home("Ondřej Malý", "12"), func(p *PlayerStats) { p.Appearances = 1 }),
+				with(away("Tomáš Černý", "1"), func(p *PlayerStats) { p.Appearances, p.Starts, p.OwnGoals, p.YellowCards = 1, 1, 1, 1 }),
+				with(away("Lukáš Bílý", "9"), func(p *PlayerStats) { p.Appearances, p.Starts, p.Goals, p.PenaltyGoals = 1, 1, 1, 1 }),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := parseMatchReport(parseHTML(t, tt.html), tt.clubType)
+			acc := newStatsAccumulator(tt.clubType)
+			acc.addMatch(report, "home", team)
+			acc.addMatch(report, "away", team)
+			got := map[string]PlayerStats{}
+			for _, p := range acc.sorted(false) {
+				got[p.Team+"/"+p.Name] = p
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("%d players, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for _, want := range tt.want {
+				if p := got[want.Team+"/"+want.Name]; !reflect.DeepEqual(p, want) {
+					t.Errorf("%s:\n got %+v\nwant %+v", want.Name, p, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStatsClubSide(t *testing.T) {
+	// On the club endpoint only the club's side is added: an opponent's own
+	// goal must not create a player of the club
+	report := parseMatchReport(parseHTML(t, reportTablesHTML), "football")
+	acc := newStatsAccumulator("football")
+	acc.addMatch(report, "home", func(string) (string, string) { return "FC Domov", "home-id" })
+	for _, p := range acc.sorted(false) {
+		if p.Name == "Tomáš Černý" || p.OwnGoals > 0 {
+			t.Errorf("opponent's own goal counted for the club: %+v", p)
+		}
+	}
+}
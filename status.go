@@ -0,0 +1,98 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MatchStatus tells what happened to a fixture. The score alone cannot:
+// upstream prints "0:0" both for unplayed matches and goalless draws.
+type MatchStatus string
+
+const (
+	StatusScheduled MatchStatus = "scheduled"
+	StatusPlayed    MatchStatus = "played"
+	StatusPostponed MatchStatus = "postponed" // odloženo
+	StatusCancelled MatchStatus = "cancelled" // zrušeno, nehráno
+	StatusForfeited MatchStatus = "forfeited" // kontumace; the score is the awarded one
+	StatusBye       MatchStatus = "bye"       // volný los, one side is not a team
+)
+
+var (
+	reScoreText = regexp.MustCompile(`\d+\s*:\s*\d+`)
+	// "3:0 K", "3:0 (k.)": a bare K right after the score marks a forfeit
+	reForfeitMark = regexp.MustCompile(`(?i)\d\s*:\s*\d+\s*\(?k\.?\)?($|[\s,;])`)
+)
+
+// defaultStatusNotes are used when the markup says no more than the marker.
+var defaultStatusNotes = map[MatchStatus]string{
+	StatusPostponed: "odloženo",
+	StatusCancelled: "zrušeno",
+	StatusForfeited: "kontumace",
+	StatusBye:       "volný los",
+}
+
+// withStatus sets Status and Note of m. marker is the text the source prints
+// in and around the score cell (e.g. "odloženo", "3:0 K", "kontumačně");
+// now decides whether a fixture with a score has been played. A score that
+// is not "home:away" is dropped, its text survives in the note.
+func withStatus(m Match, marker string, now time.Time) Match {
+	m.Status = classifyMatch(m, marker, now)
+	if m.Status != StatusPlayed && m.Status != StatusScheduled {
+		note := strings.Trim(collapseSpaces(reScoreText.ReplaceAllString(marker, "")), "()")
+		if m.Status == StatusBye || note == "" || strings.EqualFold(strings.TrimSuffix(note, "."), "k") {
+			note = defaultStatusNotes[m.Status]
+		}
+		m.Note = note
+	}
+	if _, _, ok := splitScore(m.Score); !ok {
+		m.Score = ""
+	}
+	return m
+}
+
+func classifyMatch(m Match, marker string, now time.Time) MatchStatus {
+	if isByeTeam(m.Home) || isByeTeam(m.Away) {
+		return StatusBye
+	}
+	folded := foldLabel(marker)
+	switch {
+	case strings.Contains(folded, "kontum"):
+		return StatusForfeited
+	case strings.Contains(folded, "odloz"):
+		return StatusPostponed
+	case strings.Contains(folded, "zrus") || strings.Contains(folded, "nehra") || strings.Contains(folded, "neodehr"):
+		return StatusCancelled
+	case reForfeitMark.MatchString(folded):
+		return StatusForfeited
+	}
+	if _, _, ok := splitScore(m.Score); ok && kickedOff(m, now) {
+		return StatusPlayed
+	}
+	return StatusScheduled
+}
+
+// kickedOff reports whether m has started by now. Matches without a
+// published time of day count from the end of their day.
+func kickedOff(m Match, now time.Time) bool {
+	kickoff, ok := m.KickoffTime()
+	if !ok {
+		return false
+	}
+	if m.DateOnly {
+		kickoff = kickoff.AddDate(0, 0, 1)
+	}
+	return kickoff.Before(now)
+}
+
+// byeTeams are the folded placeholder names upstream prints instead of an
+// opponent.
+var byeTeams = map[string]bool{"volno": true, "volny los": true, "bye": true}
+
+// isByeTeam recognises the placeholder opponent of a bye ("volno", "volný los").
+// Only the whole name counts: "Slavoj Volnovice" is a team. An empty name is
+// missing data, not a bye.
+func isByeTeam(name string) bool {
+	return byeTeams[strings.Trim(foldLabel(name), " .-()")]
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithStatus(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, pragueLocation)
+	past, future := "11.09.2026 20:00", "23.10.2026 20:00"
+	tests := []struct {
+		name   string
+		match  Match
+		marker string
+		status MatchStatus
+		score  string
+		note   string
+	}{
+		{"played", Match{DateTime: past, Home: "A", Away: "B", Score: "3:1"}, "3:1", StatusPlayed, "3:1", ""},
+		{"goalless draw", Match{DateTime: past, Home: "A", Away: "B", Score: "0:0"}, "0:0", StatusPlayed, "0:0", ""},
+		{"unplayed 0:0", Match{DateTime: future, Home: "A", Away: "B", Score: "0:0"}, "0:0", StatusScheduled, "0:0", ""},
+		{"no score yet", Match{DateTime: future, Home: "A", Away: "B", Score: "-:-"}, "-:-", StatusScheduled, "", ""},
+		{"date only, same day", Match{DateTime: "15.10.2026", Home: "A", Away: "B", Score: "0:0"}, "0:0", StatusScheduled, "0:0", ""},
+		{"postponed in score cell", Match{DateTime: future, Home: "A", Away: "B", Score: "odloženo"}, "odloženo", StatusPostponed, "", "odloženo"},
+		{"postponed note", Match{DateTime: future, Home: "A", Away: "B"}, " Utkání odloženo na 5.11.", StatusPostponed, "", "Utkání odloženo na 5.11."},
+		{"cancelled", Match{DateTime: past, Home: "A", Away: "B"}, "nehráno", StatusCancelled, "", "nehráno"},
+		{"forfeit K", Match{DateTime: past, Home: "A", Away: "B", Score: "3:0"}, "3:0 K", StatusForfeited, "3:0", "kontumace"},
+		{"forfeit (k.)", Match{DateTime: past, Home: "A", Away: "B", Score: "0:3"}, "0:3 (k.)", StatusForfeited, "0:3", "kontumace"},
+		{"forfeit in words", Match{DateTime: past, Home: "A", Away: "B", Score: "3:0"}, "3:0 kontumačně", StatusForfeited, "3:0", "kontumačně"},
+		{"K. in a note is no forfeit", Match{DateTime: past, Home: "A", Away: "B", Score: "2:2"}, "2:2 hráno v K. Varech", StatusPlayed, "2:2", ""},
+		{"bye", Match{DateTime: past, Home: "A", Away: "volný los"}, "", StatusBye, "", "volný los"},
+		{"bye in parentheses", Match{DateTime: past, Home: "(volno)", Away: "B"}, "", StatusBye, "", "volný los"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := withStatus(withKickoff(tt.match), tt.marker, now)
+			if m.Status != tt.status || m.Score != tt.score || m.Note != tt.note {
+				t.Errorf("status, score, note = %q, %q, %q; want %q, %q, %q", m.Status, m.Score, m.Note, tt.status, tt.score, tt.note)
+			}
+		})
+	}
+}
+
+func TestIsByeTeam(t *testing.T) {
+	tests := map[string]bool{
+		"volno":                true,
+		"Volný los":            true,
+		" VOLNY LOS ":          true,
+		"bye":                  true,
+		"":                     false,
+		"Slavoj Volnovice":     false,
+		"FC Byeshill":          false,
+		"TJ Sokol Volno Praha": false,
+	}
+	for name, want := range tests {
+		if got := isByeTeam(name); got != want {
+			t.Errorf("isByeTeam(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	streamBuffer    = 32               // messages queued per subscriber before it is dropped
+	streamKeepAlive = 30 * time.Second // comment lines keep proxies from closing idle streams
+)
+
+// streamInterval is how often a streamed club or competition is polled; set
+// with -stream-interval or STREAM_INTERVAL.
+var streamInterval = envPositiveDuration("STREAM_INTERVAL", time.Minute)
+
+// streamMaxPollers caps the clubs and competitions streamed at once
+// (STREAM_MAX_POLLERS). Every poller scrapes upstream past the cache, so
+// further targets are refused until one is released.
+var streamMaxPollers = envInt("STREAM_MAX_POLLERS", 20)
+
+var errTooManyStreams = errors.New("too many streamed clubs and competitions")
+
+// streamMessage is one server-sent event.
+type streamMessage struct {
+	Event string
+	Data  any
+}
+
+// StreamSnapshot is the "snapshot" event: every match of the watched club
+// or competition, sent to each subscriber as soon as the poller has data.
+type StreamSnapshot struct {
+	Watch   watchTarget    `json:"watch"`
+	Matches []watchedMatch `json:"matches"`
+}
+
+// streamHub runs one poller per watched club or competition, shared by all
+// of its subscribers; the poller stops with the last subscriber.
+type streamHub struct {
+	mu      sync.Mutex
+	pollers map[string]*streamPoller
+}
+
+type streamPoller struct {
+	target  watchTarget
+	cancel  context.CancelFunc
+	done    chan struct{} // closed when run returns
+	subs    map[chan streamMessage]bool
+	current map[string]watchedMatch // nil until the first successful poll
+}
+
+var streams = &streamHub{pollers: map[string]*streamPoller{}}
+
+// subscribe registers a subscriber of t, starting its poller if needed. The
+// returned function unsubscribes; the channel is closed when the subscriber
+// falls too far behind. errTooManyStreams is returned when t has no poller
+// yet and streamMaxPollers are running.
+func (h *streamHub) subscribe(t watchTarget) (<-chan streamMessage, func(), error) {
+	ch := make(chan streamMessage, streamBuffer)
+	key := t.key()
+	h.mu.Lock()
+	p, ok := h.pollers[key]
+	if !ok {
+		if len(h.pollers) >= streamMaxPollers {
+			h.mu.Unlock()
+			return nil, nil, errTooManyStreams
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		p = &streamPoller{target: t, cancel: cancel, done: make(chan struct{}), subs: map[chan streamMessage]bool{}}
+		h.pollers[key] = p
+		go h.run(ctx, p)
+	}
+	p.subs[ch] = true
+	if p.current != nil {
+		ch <- streamMessage{Event: "snapshot", Data: newStreamSnapshot(t, p.current)}
+	}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		if p.subs[ch] {
+			delete(p.subs, ch)
+			close(ch)
+		}
+		stop := len(p.subs) == 0 && h.pollers[key] == p
+		if stop {
+			p.cancel()
+			delete(h.pollers, key)
+		}
+		h.mu.Unlock()
+		// The poller takes h.mu, so it is waited for after unlocking
+		if stop {
+			<-p.done
+		}
+	}, nil
+}
+
+// run polls the target until its last subscriber leaves and broadcasts
+// score and status changes.
+func (h *streamHub) run(ctx context.Context, p *streamPoller) {
+	defer close(p.done)
+	ticker := time.NewTicker(streamInterval)
+	defer ticker.Stop()
+	for {
+		current, failed, err := scrapeWatchTarget(withFreshFetch(ctx), p.target)
+		if ctx.Err() != nil {
+			return
+		}
+		h.mu.Lock()
+		switch {
+		case err != nil:
+			log.Printf("stream: polling %s: %v", p.target.key(), err)
+			h.broadcastLocked(p, streamMessage{Event: "error", Data: map[string]string{"error": err.Error()}})
+		case p.current == nil:
+			p.current = current
+			h.broadcastLocked(p, streamMessage{Event: "snapshot", Data: newStreamSnapshot(p.target, current)})
+		default:
+			keepFailedCompetitions(p.current, current, failed)
+			for _, change := range diffMatches(p.target, p.current, current) {
+				if slices.Contains(change.Changes, changeScore) || slices.Contains(change.Changes, changeStatus) {
+					h.broadcastLocked(p, streamMessage{Event: "match", Data: change})
+				}
+			}
+			p.current = current
+		}
+		h.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// broadcastLocked queues msg for every subscriber of p; h.mu must be held.
+// A subscriber whose queue is full is disconnected rather than blocking the others.
+func (h *streamHub) broadcastLocked(p *streamPoller, msg streamMessage) {
+	for ch := range p.subs {
+		select {
+		case ch <- msg:
+		default:
+			log.Printf("stream: dropping slow subscriber of %s", p.target.key())
+			delete(p.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+func newStreamSnapshot(t watchTarget, matches map[string]watchedMatch) StreamSnapshot {
+	s := StreamSnapshot{Watch: t, Matches: make([]watchedMatch, 0, len(matches))}
+	for _, m := range matches {
+		s.Matches = append(s.Matches, m)
+	}
+	sort.SliceStable(s.Matches, func(i, j int) bool {
+		ti, _ := s.Matches[i].KickoffTime()
+		tj, _ := s.Matches[j].KickoffTime()
+		if ti.Equal(tj) {
+			return s.Matches[i].MatchID < s.Matches[j].MatchID
+		}
+		return ti.Before(tj)
+	})
+	return s
+}
+
+// getClubStream streams score and status changes of the club's matches as server-sent events
+func getClubStream(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clubID := strings.TrimSpace(vars["id"])
+	if clubID == "" {
+		http.Error(w, "Club ID is required", http.StatusBadRequest)
+		return
+	}
+	serveStream(w, r, watchTarget{Type: vars["type"], ClubID: clubID})
+}
+
+// getCompetitionStream streams score and status changes of all matches of a competition
+func getCompetitionStream(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	compID := strings.TrimSpace(vars["competitionID"])
+	if compID == "" {
+		http.Error(w, "Competition ID is required", http.StatusBadRequest)
+		return
+	}
+	serveStream(w, r, watchTarget{Type: vars["type"], CompetitionID: compID})
+}
+
+func serveStream(w http.ResponseWriter, r *http.Request, t watchTarget) {
+	if _, ok := sportParamFor(t.Type); !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	msgs, unsubscribe, err := streams.subscribe(t)
+	if err != nil {
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, int(streamInterval.Seconds()))))
+		http.Error(w, "Too many streams, try again later", http.StatusServiceUnavailable)
+		return
+	}
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(msg.Data)
+			if err != nil {
+				log.Printf("stream: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Event, data)
+		}
+		flusher.Flush()
+	}
+}
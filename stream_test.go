@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStreamPollerCap(t *testing.T) {
+	defer func(n int) { streamMaxPollers = n }(streamMaxPollers)
+	streamMaxPollers = 1
+	hub := &streamHub{pollers: map[string]*streamPoller{}}
+
+	a := watchTarget{Type: "futsal", ClubID: bizoniID}
+	_, unsubA, err := hub.subscribe(a)
+	if err != nil {
+		t.Fatalf("first target: %v", err)
+	}
+	// Further subscribers of a running poller share it
+	_, unsubShared, err := hub.subscribe(a)
+	if err != nil {
+		t.Fatalf("second subscriber of the same target: %v", err)
+	}
+	b := watchTarget{Type: "futsal", CompetitionID: realTop}
+	if _, _, err := hub.subscribe(b); !errors.Is(err, errTooManyStreams) {
+		t.Fatalf("target over the cap: err = %v, want errTooManyStreams", err)
+	}
+
+	done := hub.pollers[a.key()].done
+	unsubA()
+	unsubShared()
+	// Releasing the last subscriber waits for the poller to stop
+	select {
+	case <-done:
+	default:
+		t.Fatal("poller still running after its last subscriber left")
+	}
+	_, unsubB, err := hub.subscribe(b)
+	if err != nil {
+		t.Fatalf("target after the poller was released: %v", err)
+	}
+	unsubB()
+}
+
+func TestStreamRefusedOverCap(t *testing.T) {
+	defer func(n int) { streamMaxPollers = n }(streamMaxPollers)
+	streamMaxPollers = 0
+
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/club/futsal/"+bizoniID+"/stream", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("stream over the cap = %d (Retry-After %q), want 503 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if ct := rec.Header().Get("Content-Type"); ct == "text/event-stream" {
+		t.Errorf("refused stream was started as %s", ct)
+	}
+}
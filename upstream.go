@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Upstreams are the origins the scrapers talk to. An origin may carry a
+// path prefix (e.g. "http://127.0.0.1:8080/is"), which is how the fixture
+// server serves all three from one listener.
+type Upstreams struct {
+	Fotbal string `json:"fotbal"` // www.fotbal.cz: club pages, club search, competition pages
+	IS     string `json:"is"`     // is.fotbal.cz: fixtures, standings, match and delegation reports
+	Media  string `json:"media"`  // is1.fotbal.cz: club logos
+}
+
+func defaultUpstreams() Upstreams {
+	return Upstreams{
+		Fotbal: "https://www.fotbal.cz",
+		IS:     "https://is.fotbal.cz",
+		Media:  "https://is1.fotbal.cz",
+	}
+}
+
+// upstream is used by all URL builders below.
+var upstream = defaultUpstreams()
+
+// Config is the optional JSON configuration file passed with -config.
+type Config struct {
+	Upstreams Upstreams `json:"upstreams"`
+	Clubs     []ClubRef `json:"clubs,omitempty"` // clubs written by the export command and scanned for /venues
+}
+
+// loadConfig reads a configuration file; an empty path yields an empty Config.
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// withOverrides returns u with every non-empty origin of o applied and
+// trailing slashes removed.
+func (u Upstreams) withOverrides(o Upstreams) Upstreams {
+	set := func(dst *string, v string) {
+		if v = strings.TrimSpace(v); v != "" {
+			*dst = v
+		}
+	}
+	set(&u.Fotbal, o.Fotbal)
+	set(&u.IS, o.IS)
+	set(&u.Media, o.Media)
+	u.Fotbal = strings.TrimRight(u.Fotbal, "/")
+	u.IS = strings.TrimRight(u.IS, "/")
+	u.Media = strings.TrimRight(u.Media, "/")
+	return u
+}
+
+// upstreamsFromEnv reads FOTBAL_BASE_URL, IS_BASE_URL and MEDIA_BASE_URL.
+func upstreamsFromEnv() Upstreams {
+	return Upstreams{
+		Fotbal: os.Getenv("FOTBAL_BASE_URL"),
+		IS:     os.Getenv("IS_BASE_URL"),
+		Media:  os.Getenv("MEDIA_BASE_URL"),
+	}
+}
+
+// fotbalURL builds a www.fotbal.cz URL from an absolute path.
+func fotbalURL(format string, args ...any) string {
+	return upstream.Fotbal + fmt.Sprintf(format, args...)
+}
+
+// isURL builds an is.fotbal.cz URL from an absolute path.
+func isURL(format string, args ...any) string {
+	return upstream.IS + fmt.Sprintf(format, args...)
+}
+
+// mediaURL builds an is1.fotbal.cz URL from an absolute path.
+func mediaURL(format string, args ...any) string {
+	return upstream.Media + fmt.Sprintf(format, args...)
+}
+
+// clubLogoURL is the official logo of a club (or team) UUID.
+func clubLogoURL(clubID string) string {
+	return mediaURL("/media/kluby/%s/%s_crop.jpg", clubID, clubID)
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// configClubs are the "clubs" of the config file. Their competitions make up
+// the venue directory and feed the referee index.
+var configClubs []ClubRef
+
+// maxVenueClubs caps the clubs a request may add with ?club=.
+const maxVenueClubs = 10
+
+// Venue is one normalised venue with the clubs playing their home matches there.
+type Venue struct {
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`              // most used spelling
+	Aliases  []string    `json:"aliases,omitempty"` // other spellings of the same venue
+	Matches  int         `json:"matches"`
+	Upcoming int         `json:"upcoming"` // scheduled matches not played yet
+	Clubs    []VenueClub `json:"clubs"`
+}
+
+// VenueClub is a home team of matches at a venue.
+type VenueClub struct {
+	Type    string `json:"type"`
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Matches int    `json:"matches"`
+}
+
+// VenueMatch is a match at a venue.
+type VenueMatch struct {
+	watchedMatch
+	Type string `json:"type"`
+}
+
+// VenueList is the response of the venues endpoint.
+type VenueList struct {
+	Clubs  []ClubRef `json:"clubs"`            // clubs whose competitions were scanned
+	Errors []string  `json:"errors,omitempty"` // clubs or competitions that could not be scraped
+	Venues []Venue   `json:"venues"`
+}
+
+// VenueMatches is the response of the venue matches endpoint.
+type VenueMatches struct {
+	Venue
+	Errors   []string     `json:"errors,omitempty"`
+	Fixtures []VenueMatch `json:"fixtures"`
+}
+
+// venueAbbreviations shorten common words of venue names, so "Sportovní hala
+// Hodonín" and "SH Hodonín" become one venue. Keys are folded.
+var venueAbbreviations = []struct{ long, short string }{
+	{"mestska sportovni hala", "msh"},
+	{"sportovni hala", "sh"},
+	{"sportovni areal", "sa"},
+	{"zakladni skola", "zs"},
+	{"umela trava", "ut"},
+	{"umely travnik", "ut"},
+}
+
+var reVenueSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// venueID normalises a free-text venue into a stable ID such as
+// "sh-uherske-hradiste"; "" for an empty venue.
+func venueID(venue string) string {
+	key := " " + reVenueSeparators.ReplaceAllString(foldLabel(venue), " ") + " "
+	for _, a := range venueAbbreviations {
+		key = strings.ReplaceAll(key, " "+a.long+" ", " "+a.short+" ")
+	}
+	return strings.Join(strings.Fields(key), "-")
+}
+
+// getVenues lists the venues of all matches in the competitions of the
+// configured clubs; ?club=type:id adds clubs.
+func getVenues(w http.ResponseWriter, r *http.Request) {
+	clubs, ok := venueClubsFromRequest(w, r)
+	if !ok {
+		return
+	}
+	matches, errs := venueMatches(r.Context(), clubs)
+	list := &VenueList{Clubs: clubs, Errors: errs, Venues: buildVenues(matches, time.Now())}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// getVenueMatches lists every known fixture at one venue, oldest first;
+// ?upcoming=1 keeps only matches not played yet.
+func getVenueMatches(w http.ResponseWriter, r *http.Request) {
+	id := strings.ToLower(strings.TrimSpace(mux.Vars(r)["id"]))
+	if id == "" {
+		http.Error(w, "Venue ID is required", http.StatusBadRequest)
+		return
+	}
+	clubs, ok := venueClubsFromRequest(w, r)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	matches, errs := venueMatches(r.Context(), clubs)
+	var at []VenueMatch
+	for _, m := range matches {
+		if venueID(m.Venue) == id {
+			at = append(at, m)
+		}
+	}
+	venues := buildVenues(at, now)
+	if len(venues) == 0 {
+		http.Error(w, "Venue not found", http.StatusNotFound)
+		return
+	}
+
+	resp := &VenueMatches{Venue: venues[0], Errors: errs, Fixtures: []VenueMatch{}}
+	upcoming := queryFlag(r, "upcoming")
+	for _, m := range at {
+		if !upcoming || (m.Status == StatusScheduled && !kickedOff(m.Match, now)) {
+			resp.Fixtures = append(resp.Fixtures, m)
+		}
+	}
+	sort.SliceStable(resp.Fixtures, func(i, j int) bool {
+		ti, _ := resp.Fixtures[i].KickoffTime()
+		tj, _ := resp.Fixtures[j].KickoffTime()
+		return ti.Before(tj)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// venueClubsFromRequest returns the configured clubs plus those of ?club=;
+// it writes an error response when there are none or one is invalid.
+func venueClubsFromRequest(w http.ResponseWriter, r *http.Request) ([]ClubRef, bool) {
+	clubs := append([]ClubRef{}, configClubs...)
+	added := 0
+	for _, v := range r.URL.Query()["club"] {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			if added++; added > maxVenueClubs {
+				http.Error(w, fmt.Sprintf("At most %d clubs can be given with club", maxVenueClubs), http.StatusBadRequest)
+				return nil, false
+			}
+			clubType, id, ok := strings.Cut(part, ":")
+			if _, valid := sportParamFor(clubType); !ok || !valid || id == "" {
+				http.Error(w, "club must be given as type:id, e.g. futsal:441d3783-06aa-436a-b438-359300ee0371", http.StatusBadRequest)
+				return nil, false
+			}
+			clubs = append(clubs, ClubRef{Type: clubType, ID: id})
+		}
+	}
+	if len(clubs) == 0 {
+		http.Error(w, "No clubs to scan: list them in the config file or pass ?club=type:id", http.StatusBadRequest)
+		return nil, false
+	}
+	return clubs, true
+}
+
+// venueScan is a cached result of scanVenueMatches.
+type venueScan struct {
+	Matches []VenueMatch `json:"matches"`
+	Errors  []string     `json:"errors,omitempty"`
+}
+
+// venueMatches returns the matches of scanVenueMatches, reusing a scan of
+// the same clubs for the fixtures TTL, so the venue list and the venue
+// pages share one scan. Scans with errors are not kept.
+func venueMatches(ctx context.Context, clubs []ClubRef) ([]VenueMatch, []string) {
+	keys := make([]string, len(clubs))
+	for i, c := range clubs {
+		keys[i] = c.Type + ":" + strings.ToLower(c.ID)
+	}
+	sort.Strings(keys)
+	key := "venues:" + strings.Join(slices.Compact(keys), ",")
+
+	var scan venueScan
+	if cachedEntity(ctx, key, activeTTLs.Fixtures, &scan) {
+		return scan.Matches, scan.Errors
+	}
+	matches, errs := scanVenueMatches(ctx, clubs)
+	if len(errs) == 0 {
+		storeEntity(key, venueScan{Matches: matches})
+	}
+	return matches, errs
+}
+
+// scanVenueMatches scrapes every fixture of the competitions of clubs. Each
+// competition is scraped once even when several clubs play in it.
+func scanVenueMatches(ctx context.Context, clubs []ClubRef) ([]VenueMatch, []string) {
+	pages := make([]*ClubInfo, len(clubs))
+	pageErrs := make([]string, len(clubs))
+	forEachLimited(len(clubs), scrapeConcurrency, func(i int) {
+		club, err := scrapeClubPage(ctx, clubs[i].Type, clubs[i].ID)
+		if err != nil {
+			log.Printf("error fetching club %s: %v", clubs[i].ID, err)
+			pageErrs[i] = clubs[i].Type + ":" + clubs[i].ID + ": " + err.Error()
+			return
+		}
+		pages[i] = club
+	})
+
+	type scan struct {
+		clubType string
+		comp     Competition
+	}
+	var errs []string
+	var scans []scan
+	seen := map[string]bool{}
+	for i, club := range pages {
+		if club == nil {
+			errs = append(errs, pageErrs[i])
+			continue
+		}
+		for _, comp := range club.Competitions {
+			if key := clubs[i].Type + ":" + comp.ID; !seen[key] {
+				seen[key] = true
+				scans = append(scans, scan{clubType: clubs[i].Type, comp: comp})
+			}
+		}
+	}
+
+	found := make([][]VenueMatch, len(scans))
+	scanErrs := make([]string, len(scans))
+	forEachLimited(len(scans), scrapeConcurrency, func(i int) {
+		s := scans[i]
+		sportParam, _ := sportParamFor(s.clubType)
+		matches, err := competitionMatches(ctx, s.comp, s.clubType, sportParam, "", "")
+		if err != nil {
+			log.Printf("error fetching matches for %s: %v", s.comp.ID, err)
+			scanErrs[i] = s.comp.ID + ": " + err.Error()
+			return
+		}
+		for _, m := range matches {
+			if m.Status != StatusBye && venueID(m.Venue) != "" {
+				found[i] = append(found[i], VenueMatch{watchedMatch: watchedMatch{Match: m, CompetitionID: s.comp.ID, CompetitionName: s.comp.Name}, Type: s.clubType})
+			}
+		}
+	})
+
+	var all []VenueMatch
+	for i := range scans {
+		if scanErrs[i] != "" {
+			errs = append(errs, scanErrs[i])
+		}
+		all = append(all, found[i]...)
+	}
+	return all, errs
+}
+
+// buildVenues groups matches by venueID, sorted by venue name.
+func buildVenues(matches []VenueMatch, now time.Time) []Venue {
+	type group struct {
+		venue     *Venue
+		spellings map[string]int
+		clubs     map[string]*VenueClub
+		clubOrder []string
+	}
+	groups := map[string]*group{}
+	var order []string
+	for _, m := range matches {
+		id := venueID(m.Venue)
+		g, ok := groups[id]
+		if !ok {
+			g = &group{venue: &Venue{ID: id}, spellings: map[string]int{}, clubs: map[string]*VenueClub{}}
+			groups[id] = g
+			order = append(order, id)
+		}
+		g.spellings[collapseSpaces(m.Venue)]++
+		g.venue.Matches++
+		if m.Status == StatusScheduled && !kickedOff(m.Match, now) {
+			g.venue.Upcoming++
+		}
+		key := m.Type + ":" + strings.ToLower(m.HomeID)
+		if m.HomeID == "" {
+			key = m.Type + ":" + foldLabel(m.Home)
+		}
+		c, ok := g.clubs[key]
+		if !ok {
+			c = &VenueClub{Type: m.Type, ID: m.HomeID, Name: m.Home}
+			g.clubs[key] = c
+			g.clubOrder = append(g.clubOrder, key)
+		}
+		c.Matches++
+	}
+
+	venues := []Venue{}
+	for _, id := range order {
+		g := groups[id]
+		for spelling, n := range g.spellings {
+			if best := g.spellings[g.venue.Name]; n > best || (n == best && spelling < g.venue.Name) {
+				g.venue.Name = spelling
+			}
+		}
+		for spelling := range g.spellings {
+			if spelling != g.venue.Name {
+				g.venue.Aliases = append(g.venue.Aliases, spelling)
+			}
+		}
+		sort.Strings(g.venue.Aliases)
+		for _, key := range g.clubOrder {
+			g.venue.Clubs = append(g.venue.Clubs, *g.clubs[key])
+		}
+		sort.SliceStable(g.venue.Clubs, func(i, j int) bool { return g.venue.Clubs[i].Matches > g.venue.Clubs[j].Matches })
+		venues = append(venues, *g.venue)
+	}
+	sort.SliceStable(venues, func(i, j int) bool { return foldLabel(venues[i].Name) < foldLabel(venues[j].Name) })
+	return venues
+}
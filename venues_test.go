@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestVenueScanIsReused(t *testing.T) {
+	var list VenueList
+	getJSON(t, "/venues?club=futsal:"+bizoniID, &list)
+	if len(list.Errors) > 0 || len(list.Venues) == 0 {
+		t.Fatalf("venues = %+v", list)
+	}
+	scanned := pages.count.Load()
+
+	var at VenueMatches
+	getJSON(t, "/venues/"+list.Venues[0].ID+"/matches?club=futsal:"+bizoniID, &at)
+	if len(at.Fixtures) != list.Venues[0].Matches {
+		t.Errorf("%d fixtures at %s, want %d", len(at.Fixtures), at.ID, list.Venues[0].Matches)
+	}
+	if n := pages.count.Load(); n != scanned {
+		t.Errorf("venue matches fetched %d more pages instead of reusing the scan", n-scanned)
+	}
+}
+
+func TestVenueClubLimit(t *testing.T) {
+	clubs := make([]string, maxVenueClubs+1)
+	for i := range clubs {
+		clubs[i] = "futsal:" + bizoniID
+	}
+	if code := getStatus("/venues?club=" + strings.Join(clubs, ",")); code != http.StatusBadRequest {
+		t.Errorf("%d clubs = %d, want 400", len(clubs), code)
+	}
+}
@@ -0,0 +1,689 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Kinds of match changes a webhook can subscribe to.
+const (
+	changeAdded       = "added"
+	changeRescheduled = "rescheduled"
+	changeScore       = "score"
+	changeVenue       = "venue"
+	changeStatus      = "status"
+)
+
+var matchChangeKinds = []string{changeAdded, changeRescheduled, changeScore, changeVenue, changeStatus}
+
+const (
+	webhookMaxAttempts = 5
+	webhookRetryDelay  = 5 * time.Second // doubled after every failed attempt
+)
+
+// Webhook is a registered notification target for one club or competition.
+type Webhook struct {
+	ID            string           `json:"id"`
+	Type          string           `json:"type"`
+	ClubID        string           `json:"club_id,omitempty"`
+	CompetitionID string           `json:"competition_id,omitempty"`
+	URL           string           `json:"url"`
+	Secret        string           `json:"secret,omitempty"` // only shown when registering
+	Events        []string         `json:"events,omitempty"` // change kinds, empty for all
+	CreatedAt     time.Time        `json:"created_at"`
+	LastDelivery  *WebhookDelivery `json:"last_delivery,omitempty"`
+}
+
+// WebhookDelivery is the outcome of the latest delivery to a webhook.
+type WebhookDelivery struct {
+	ID         string    `json:"id"`
+	At         time.Time `json:"at"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// WebhookPayload is the JSON body POSTed to a webhook, one per changed match.
+type WebhookPayload struct {
+	DeliveryID string    `json:"delivery_id"`
+	WebhookID  string    `json:"webhook_id"`
+	Event      string    `json:"event"` // match.added or match.changed
+	SentAt     time.Time `json:"sent_at"`
+	MatchChange
+}
+
+// MatchChange is one match that appeared or changed between two polls of a
+// watched club or competition.
+type MatchChange struct {
+	Watch           watchTarget `json:"watch"`
+	Changes         []string    `json:"changes"`
+	CompetitionID   string      `json:"competition_id"`
+	CompetitionName string      `json:"competition_name,omitempty"`
+	Match           Match       `json:"match"`
+	Previous        *Match      `json:"previous,omitempty"`
+}
+
+// watchTarget is a club or a competition whose fixtures are polled.
+type watchTarget struct {
+	Type          string `json:"type"`
+	ClubID        string `json:"club_id,omitempty"`
+	CompetitionID string `json:"competition_id,omitempty"`
+}
+
+func (t watchTarget) key() string {
+	if t.ClubID != "" {
+		return "club/" + t.Type + "/" + strings.ToLower(t.ClubID)
+	}
+	return "competition/" + t.Type + "/" + strings.ToLower(t.CompetitionID)
+}
+
+// watchedMatch is a match in a poll snapshot, with its competition.
+type watchedMatch struct {
+	Match
+	CompetitionID   string `json:"competition_id"`
+	CompetitionName string `json:"competition_name,omitempty"`
+}
+
+// webhookRegistry keeps the webhooks and the last fixture snapshot of every
+// watched target, optionally persisted to a JSON file.
+type webhookRegistry struct {
+	client *http.Client
+
+	mu        sync.Mutex
+	path      string
+	hooks     map[string]*Webhook
+	snapshots map[string]map[string]watchedMatch // target key -> match ID -> match
+	polling   map[string]bool
+}
+
+// webhookFile is the persisted state of the registry.
+type webhookFile struct {
+	Webhooks  []*Webhook                         `json:"webhooks"`
+	Snapshots map[string]map[string]watchedMatch `json:"snapshots"`
+}
+
+// webhooks is used by the webhook endpoints and the poller started by main.
+var webhooks = newWebhookRegistry()
+
+func newWebhookRegistry() *webhookRegistry {
+	return &webhookRegistry{
+		client:    newWebhookClient(),
+		hooks:     map[string]*Webhook{},
+		snapshots: map[string]map[string]watchedMatch{},
+		polling:   map[string]bool{},
+	}
+}
+
+// load reads the registry from path, which is also where later changes are
+// saved. A missing file is an empty registry.
+func (reg *webhookRegistry) load(path string) error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var f webhookFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for _, hook := range f.Webhooks {
+		reg.hooks[hook.ID] = hook
+	}
+	if f.Snapshots != nil {
+		reg.snapshots = f.Snapshots
+	}
+	return nil
+}
+
+// saveLocked writes the registry file; reg.mu must be held.
+func (reg *webhookRegistry) saveLocked() {
+	if reg.path == "" {
+		return
+	}
+	f := webhookFile{Webhooks: reg.sortedLocked(), Snapshots: reg.snapshots}
+	body, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		log.Printf("webhooks: %v", err)
+		return
+	}
+	// Write and rename, so a crash never leaves a truncated file behind
+	tmp := reg.path + ".tmp"
+	err = os.MkdirAll(filepath.Dir(reg.path), 0o755)
+	if err == nil {
+		err = os.WriteFile(tmp, body, 0o600)
+	}
+	if err == nil {
+		err = os.Rename(tmp, reg.path)
+	}
+	if err != nil {
+		log.Printf("webhooks: saving %s: %v", reg.path, err)
+	}
+}
+
+func (reg *webhookRegistry) sortedLocked() []*Webhook {
+	hooks := make([]*Webhook, 0, len(reg.hooks))
+	for _, hook := range reg.hooks {
+		hooks = append(hooks, hook)
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].CreatedAt.Before(hooks[j].CreatedAt) })
+	return hooks
+}
+
+func (reg *webhookRegistry) add(hook *Webhook) {
+	reg.mu.Lock()
+	reg.hooks[hook.ID] = hook
+	reg.saveLocked()
+	reg.mu.Unlock()
+}
+
+// remove deletes a webhook and the snapshot of its target once nothing watches it.
+func (reg *webhookRegistry) remove(id string) bool {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	hook, ok := reg.hooks[id]
+	if !ok {
+		return false
+	}
+	delete(reg.hooks, id)
+	if _, watched := reg.hooksForLocked(hookTarget(hook)); !watched {
+		delete(reg.snapshots, hookTarget(hook).key())
+	}
+	reg.saveLocked()
+	return true
+}
+
+// list returns the webhooks without their secrets.
+func (reg *webhookRegistry) list() []Webhook {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	hooks := []Webhook{}
+	for _, hook := range reg.sortedLocked() {
+		h := *hook
+		h.Secret = ""
+		hooks = append(hooks, h)
+	}
+	return hooks
+}
+
+func hookTarget(hook *Webhook) watchTarget {
+	return watchTarget{Type: hook.Type, ClubID: hook.ClubID, CompetitionID: hook.CompetitionID}
+}
+
+// targetsLocked lists every watched target once; reg.mu must be held.
+func (reg *webhookRegistry) targetsLocked() []watchTarget {
+	seen := map[string]bool{}
+	var targets []watchTarget
+	for _, hook := range reg.sortedLocked() {
+		t := hookTarget(hook)
+		if !seen[t.key()] {
+			seen[t.key()] = true
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// run polls all watched targets every interval until ctx is done.
+func (reg *webhookRegistry) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		reg.mu.Lock()
+		targets := reg.targetsLocked()
+		reg.mu.Unlock()
+		forEachLimited(len(targets), scrapeConcurrency, func(i int) {
+			reg.poll(ctx, targets[i])
+		})
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll scrapes one target bypassing the page cache, diffs it against the
+// previous snapshot and notifies the target's webhooks. The first poll of a
+// target only records the snapshot.
+func (reg *webhookRegistry) poll(ctx context.Context, t watchTarget) {
+	key := t.key()
+	reg.mu.Lock()
+	if reg.polling[key] {
+		reg.mu.Unlock()
+		return
+	}
+	reg.polling[key] = true
+	reg.mu.Unlock()
+	defer func() {
+		reg.mu.Lock()
+		delete(reg.polling, key)
+		reg.mu.Unlock()
+	}()
+
+	current, failed, err := scrapeWatchTarget(withFreshFetch(ctx), t)
+	if err != nil {
+		log.Printf("webhooks: polling %s: %v", key, err)
+		return
+	}
+
+	reg.mu.Lock()
+	previous, primed := reg.snapshots[key]
+	keepFailedCompetitions(previous, current, failed)
+	hooks, watched := reg.hooksForLocked(t)
+	if !watched {
+		// The last webhook was removed while polling
+		reg.mu.Unlock()
+		return
+	}
+	var changes []MatchChange
+	if primed {
+		changes = diffMatches(t, previous, current)
+	}
+	reg.snapshots[key] = current
+	var deliveries []func()
+	for _, change := range changes {
+		for _, hook := range hooks {
+			if wantsChange(hook, change) {
+				target := *hook
+				payload := WebhookPayload{DeliveryID: randomHex(16), WebhookID: hook.ID, MatchChange: change}
+				deliveries = append(deliveries, func() { reg.deliver(ctx, target, payload) })
+			}
+		}
+	}
+	if !primed || len(changes) > 0 {
+		reg.saveLocked()
+	}
+	reg.mu.Unlock()
+
+	if len(changes) > 0 {
+		log.Printf("webhooks: %s: %d changed matches, %d deliveries", key, len(changes), len(deliveries))
+	}
+	for _, deliver := range deliveries {
+		go deliver()
+	}
+}
+
+func (reg *webhookRegistry) hooksForLocked(t watchTarget) ([]*Webhook, bool) {
+	var hooks []*Webhook
+	for _, hook := range reg.sortedLocked() {
+		if hookTarget(hook).key() == t.key() {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, len(hooks) > 0
+}
+
+func wantsChange(hook *Webhook, change MatchChange) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, kind := range change.Changes {
+		if slices.Contains(hook.Events, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver POSTs payload to the webhook, retrying with exponential backoff,
+// and records the outcome on the webhook.
+func (reg *webhookRegistry) deliver(ctx context.Context, hook Webhook, payload WebhookPayload) {
+	payload.Event = "match.changed"
+	if slices.Contains(payload.Changes, changeAdded) {
+		payload.Event = "match.added"
+	}
+	result := WebhookDelivery{ID: payload.DeliveryID}
+	delay := webhookRetryDelay
+	for result.Attempts < webhookMaxAttempts {
+		if result.Attempts > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		result.Attempts++
+		payload.SentAt = time.Now().UTC()
+		result.StatusCode, result.Error = 0, ""
+		status, err := reg.post(ctx, hook, payload)
+		result.StatusCode = status
+		if err == nil {
+			break
+		}
+		result.Error = err.Error()
+		log.Printf("webhooks: delivery %s to %s failed (attempt %d): %v", payload.DeliveryID, hook.URL, result.Attempts, err)
+	}
+	result.At = time.Now().UTC()
+
+	reg.mu.Lock()
+	if stored, ok := reg.hooks[hook.ID]; ok {
+		stored.LastDelivery = &result
+		reg.saveLocked()
+	}
+	reg.mu.Unlock()
+}
+
+// post sends one signed attempt; any non-2xx answer is an error.
+func (reg *webhookRegistry) post(ctx context.Context, hook Webhook, payload WebhookPayload) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "facr-scraper-webhooks")
+	req.Header.Set("X-Webhook-Event", payload.Event)
+	req.Header.Set("X-Webhook-Delivery", payload.DeliveryID)
+	req.Header.Set("X-Webhook-Signature", signPayload(hook.Secret, body))
+	resp, err := reg.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("received status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// newWebhookClient returns the client deliveries are posted with. It only
+// dials public addresses, so a webhook cannot reach the host or its network,
+// not even through DNS changes or redirects after registration.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: dialPublicOnly}
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+	}
+}
+
+var errPrivateAddress = errors.New("webhook targets must not be loopback, private or link-local addresses")
+
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !publicAddr(addr) {
+		return fmt.Errorf("%w: %s", errPrivateAddress, host)
+	}
+	return nil
+}
+
+// publicAddr reports whether addr is a globally routable unicast address:
+// not loopback, private, link-local, multicast or unspecified.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// checkWebhookHost resolves the host of a webhook URL and rejects it unless
+// every address is public.
+func checkWebhookHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !publicAddr(addr) {
+			return fmt.Errorf("%w: %s", errPrivateAddress, host)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s", host)
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", errPrivateAddress, host, addr)
+		}
+	}
+	return nil
+}
+
+// signPayload is the X-Webhook-Signature header: "sha256=" and the hex
+// HMAC-SHA256 of the body keyed with the webhook secret.
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// scrapeWatchTarget collects the fixtures of a club or competition by match
+// ID. failed lists the competitions of a club that could not be scraped.
+func scrapeWatchTarget(ctx context.Context, t watchTarget) (map[string]watchedMatch, map[string]bool, error) {
+	matches := map[string]watchedMatch{}
+	failed := map[string]bool{}
+	add := func(comp Competition) {
+		for _, m := range comp.Matches {
+			if m.MatchID != "" {
+				matches[m.MatchID] = watchedMatch{Match: m, CompetitionID: comp.ID, CompetitionName: comp.Name}
+			}
+		}
+	}
+	if t.ClubID != "" {
+		club, err := scrapeClubInfo(ctx, t.Type, t.ClubID, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, comp := range club.Competitions {
+			if comp.Error != "" {
+				failed[comp.ID] = true
+			}
+			add(comp)
+		}
+		return matches, failed, nil
+	}
+	info, err := scrapeCompetition(ctx, t.Type, t.CompetitionID, tableOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Error != "" {
+		return nil, nil, errors.New(info.Error)
+	}
+	add(info.Competition)
+	return matches, failed, nil
+}
+
+// keepFailedCompetitions copies the previous matches of competitions that
+// failed this time into current, so they do not come back as "added" on the
+// next successful poll.
+func keepFailedCompetitions(previous, current map[string]watchedMatch, failed map[string]bool) {
+	for id, m := range previous {
+		if _, ok := current[id]; !ok && failed[m.CompetitionID] {
+			current[id] = m
+		}
+	}
+}
+
+// diffMatches lists the matches of current that are new or differ from
+// previous in kickoff, score, venue or status, oldest kickoff first.
+// Matches that disappeared are not reported.
+func diffMatches(t watchTarget, previous, current map[string]watchedMatch) []MatchChange {
+	var changes []MatchChange
+	for id, cur := range current {
+		change := MatchChange{Watch: t, CompetitionID: cur.CompetitionID, CompetitionName: cur.CompetitionName, Match: cur.Match}
+		prev, ok := previous[id]
+		if !ok {
+			change.Changes = []string{changeAdded}
+			changes = append(changes, change)
+			continue
+		}
+		if prev.Kickoff != cur.Kickoff || prev.DateTime != cur.DateTime {
+			change.Changes = append(change.Changes, changeRescheduled)
+		}
+		// A status change to played counts as a score, "0:0" is printed before kickoff too
+		played := matchPlayed(cur.Match, time.Now())
+		if played && (prev.Score != cur.Score || !matchPlayed(prev.Match, time.Now())) {
+			change.Changes = append(change.Changes, changeScore)
+		}
+		if prev.Venue != cur.Venue {
+			change.Changes = append(change.Changes, changeVenue)
+		}
+		if prev.Status != cur.Status && !(played && slices.Contains(change.Changes, changeScore)) {
+			change.Changes = append(change.Changes, changeStatus)
+		}
+		if len(change.Changes) > 0 {
+			prevMatch := prev.Match
+			change.Previous = &prevMatch
+			changes = append(changes, change)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		ti, _ := changes[i].Match.KickoffTime()
+		tj, _ := changes[j].Match.KickoffTime()
+		if ti.Equal(tj) {
+			return changes[i].Match.MatchID < changes[j].Match.MatchID
+		}
+		return ti.Before(tj)
+	})
+	return changes
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// webhookRequest is the body of POST /webhooks.
+type webhookRequest struct {
+	Type          string   `json:"type"`
+	ClubID        string   `json:"club_id"`
+	CompetitionID string   `json:"competition_id"`
+	URL           string   `json:"url"`
+	Secret        string   `json:"secret"`
+	Events        []string `json:"events"`
+}
+
+// adminToken guards the webhook endpoints (-admin-token, env ADMIN_TOKEN).
+// Without one they are disabled: a webhook makes the server send requests
+// to URLs chosen by the caller.
+var adminToken string
+
+// requireAdmin checks the "Authorization: Bearer <token>" header and writes
+// the error response when it does not carry the admin token.
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if adminToken == "" {
+		http.Error(w, "Webhooks are disabled. Start the server with -admin-token or ADMIN_TOKEN.", http.StatusForbidden)
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(adminToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Admin token required", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// postWebhook registers a webhook for a club or competition
+func postWebhook(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	var req webhookRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	req.ClubID = strings.TrimSpace(req.ClubID)
+	req.CompetitionID = strings.TrimSpace(req.CompetitionID)
+	if _, ok := sportParamFor(req.Type); !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+	if (req.ClubID == "") == (req.CompetitionID == "") {
+		http.Error(w, "Exactly one of club_id and competition_id is required", http.StatusBadRequest)
+		return
+	}
+	u, err := neturl.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		http.Error(w, "url must be an absolute http(s) URL", http.StatusBadRequest)
+		return
+	}
+	if err := checkWebhookHost(r.Context(), u.Hostname()); err != nil {
+		http.Error(w, "Invalid url: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, kind := range req.Events {
+		if !slices.Contains(matchChangeKinds, kind) {
+			http.Error(w, fmt.Sprintf("Unknown event %q. Use %s.", kind, strings.Join(matchChangeKinds, ", ")), http.StatusBadRequest)
+			return
+		}
+	}
+	hook := &Webhook{
+		ID:            randomHex(8),
+		Type:          req.Type,
+		ClubID:        req.ClubID,
+		CompetitionID: req.CompetitionID,
+		URL:           req.URL,
+		Secret:        req.Secret,
+		Events:        req.Events,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if hook.Secret == "" {
+		hook.Secret = randomHex(32)
+	}
+	webhooks.add(hook)
+	// Record the first snapshot now, so changes are reported from the next poll on
+	go webhooks.poll(context.Background(), hookTarget(hook))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hook)
+}
+
+// getWebhooks lists the registered webhooks without their secrets
+func getWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	hooks := webhooks.list()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"count": len(hooks), "webhooks": hooks})
+}
+
+// deleteWebhook unregisters a webhook
+func deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	if !webhooks.remove(mux.Vars(r)["id"]) {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookAdminToken(t *testing.T) {
+	defer func(token string) { adminToken = token }(adminToken)
+	body := `{"type":"futsal","club_id":"` + bizoniID + `","url":"http://127.0.0.1:9/hook"}`
+
+	tests := []struct {
+		name   string
+		token  string // configured
+		header string // Authorization
+		method string
+		path   string
+		want   int
+	}{
+		{"disabled without token", "", "Bearer x", http.MethodGet, "/webhooks", http.StatusForbidden},
+		{"missing header", "s3cret", "", http.MethodGet, "/webhooks", http.StatusUnauthorized},
+		{"wrong token", "s3cret", "Bearer nope", http.MethodDelete, "/webhooks/abc", http.StatusUnauthorized},
+		{"list", "s3cret", "Bearer s3cret", http.MethodGet, "/webhooks", http.StatusOK},
+		{"delete unknown", "s3cret", "Bearer s3cret", http.MethodDelete, "/webhooks/abc", http.StatusNotFound},
+		{"loopback target", "s3cret", "Bearer s3cret", http.MethodPost, "/webhooks", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminToken = tt.token
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(body))
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			api.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestWebhookHostCheck(t *testing.T) {
+	for host, public := range map[string]bool{
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"192.168.0.10":    false,
+		"172.16.5.4":      false,
+		"169.254.169.254": false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"0.0.0.0":         false,
+		"::ffff:10.0.0.1": false,
+		"224.0.0.1":       false,
+		"localhost":       false,
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+	} {
+		err := checkWebhookHost(context.Background(), host)
+		if public && err != nil {
+			t.Errorf("checkWebhookHost(%q) = %v, want nil", host, err)
+		}
+		if !public && err == nil {
+			t.Errorf("checkWebhookHost(%q) accepted a non-public host", host)
+		}
+	}
+}
+
+func TestWebhookClientRefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("delivery reached a loopback server")
+	}))
+	defer srv.Close()
+
+	// A hook registered while its host was public must not reach a private
+	// address later (DNS change, redirect)
+	reg := newWebhookRegistry()
+	_, err := reg.post(context.Background(), Webhook{URL: srv.URL, Secret: "x"}, WebhookPayload{DeliveryID: "d"})
+	if !errors.Is(err, errPrivateAddress) {
+		t.Errorf("err = %v, want errPrivateAddress", err)
+	}
+}
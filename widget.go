@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const maxWidgetCount = 20
+
+// ClubWidget is the compact response of the next and last match endpoints.
+type ClubWidget struct {
+	ClubID   string        `json:"club_id"`
+	ClubType string        `json:"club_type"`
+	Name     string        `json:"name"`
+	LogoURL  string        `json:"logo_url,omitempty"`
+	Matches  []WidgetMatch `json:"matches"`
+	Errors   []string      `json:"errors,omitempty"` // competitions that could not be scraped
+}
+
+// WidgetMatch is a match reduced to what a fixture or result widget shows.
+type WidgetMatch struct {
+	Competition     string      `json:"competition"`
+	CompetitionCode string      `json:"competition_code,omitempty"`
+	CompetitionID   string      `json:"competition_id"`
+	DateTime        string      `json:"date_time"`
+	Kickoff         string      `json:"kickoff,omitempty"`
+	DateOnly        bool        `json:"date_only,omitempty"`
+	Home            string      `json:"home"`
+	HomeLogoURL     string      `json:"home_logo_url,omitempty"`
+	Away            string      `json:"away"`
+	AwayLogoURL     string      `json:"away_logo_url,omitempty"`
+	IsHome          bool        `json:"is_home"` // the club plays at home
+	Score           string      `json:"score,omitempty"`
+	Status          MatchStatus `json:"status"`
+	Note            string      `json:"note,omitempty"`
+	Venue           string      `json:"venue,omitempty"`
+	MatchID         string      `json:"match_id,omitempty"`
+}
+
+// getClubNext returns the club's next fixtures
+func getClubNext(w http.ResponseWriter, r *http.Request) {
+	serveClubWidget(w, r, true)
+}
+
+// getClubLast returns the club's most recent results
+func getClubLast(w http.ResponseWriter, r *http.Request) {
+	serveClubWidget(w, r, false)
+}
+
+func serveClubWidget(w http.ResponseWriter, r *http.Request, next bool) {
+	vars := mux.Vars(r)
+	clubID := vars["id"]
+	clubType := vars["type"]
+	if clubID == "" {
+		http.Error(w, "Club ID is required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := sportParamFor(clubType); !ok {
+		http.Error(w, "Invalid club type. Use 'football' or 'futsal'.", http.StatusBadRequest)
+		return
+	}
+	count := 1
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "count must be a positive number", http.StatusBadRequest)
+			return
+		}
+		count = min(n, maxWidgetCount)
+	}
+
+	widget, err := clubWidget(r.Context(), clubType, clubID, competitionFilterFromRequest(r), next, count, time.Now())
+	if err != nil {
+		writeFetchError(w, "club data", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(widget)
+}
+
+// clubWidget collects the next (or last) count matches of a club. filter
+// restricts the competitions by ID or code; only those competitions are
+// scraped.
+func clubWidget(ctx context.Context, clubType, clubID string, filter []string, next bool, count int, now time.Time) (*ClubWidget, error) {
+	club, err := scrapeClubPage(ctx, clubType, clubID)
+	if err != nil {
+		return nil, err
+	}
+	sportParam, _ := sportParamFor(clubType)
+
+	var comps []Competition
+	for _, comp := range club.Competitions {
+		if len(filter) == 0 || competitionSelected(comp, filter) {
+			comps = append(comps, comp)
+		}
+	}
+	found := make([][]WidgetMatch, len(comps))
+	errs := make([]string, len(comps))
+	forEachLimited(len(comps), scrapeConcurrency, func(i int) {
+		comp := comps[i]
+		matches, err := competitionMatches(ctx, comp, clubType, sportParam, club.Name, clubID)
+		if err != nil {
+			log.Printf("error fetching matches for %s: %v", comp.ID, err)
+			errs[i] = comp.ID + ": " + err.Error()
+			return
+		}
+		for _, m := range matches {
+			_, ok := m.KickoffTime()
+			if !ok || (next && (m.Status != StatusScheduled || kickedOff(m, now))) || (!next && !matchPlayed(m, now)) {
+				continue
+			}
+			found[i] = append(found[i], widgetMatch(ctx, comp, m, club.Name, clubID))
+		}
+	})
+
+	widget := &ClubWidget{ClubID: clubID, ClubType: clubType, Name: club.Name, LogoURL: club.LogoURL}
+	for i := range comps {
+		if errs[i] != "" {
+			widget.Errors = append(widget.Errors, errs[i])
+		}
+	}
+	widget.Matches = pickWidgetMatches(found, next, count)
+	return widget, nil
+}
+
+// pickWidgetMatches merges the matches found per competition, once per match
+// ID, and keeps the count soonest fixtures or newest results.
+func pickWidgetMatches(found [][]WidgetMatch, next bool, count int) []WidgetMatch {
+	matches := []WidgetMatch{}
+	seen := map[string]bool{}
+	for _, comp := range found {
+		for _, m := range comp {
+			if m.MatchID != "" && seen[m.MatchID] {
+				continue
+			}
+			seen[m.MatchID] = true
+			matches = append(matches, m)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		ti, _, _ := parseKickoff(matches[i].DateTime)
+		tj, _, _ := parseKickoff(matches[j].DateTime)
+		if next {
+			return ti.Before(tj)
+		}
+		return ti.After(tj)
+	})
+	return matches[:min(count, len(matches))]
+}
+
+// competitionFilterFromRequest reads ?competition=, competition IDs or
+// codes, comma separated or repeated.
+func competitionFilterFromRequest(r *http.Request) []string {
+	var filter []string
+	for _, v := range r.URL.Query()["competition"] {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				filter = append(filter, part)
+			}
+		}
+	}
+	return filter
+}
+
+// competitionSelected matches a competition against IDs or codes.
+func competitionSelected(comp Competition, filter []string) bool {
+	for _, f := range filter {
+		if strings.EqualFold(f, comp.ID) || (comp.Code != "" && strings.EqualFold(f, comp.Code)) {
+			return true
+		}
+	}
+	return false
+}
+
+func widgetMatch(ctx context.Context, comp Competition, m Match, clubName, clubID string) WidgetMatch {
+	wm := WidgetMatch{
+		Competition:     comp.Name,
+		CompetitionCode: comp.Code,
+		CompetitionID:   comp.ID,
+		DateTime:        m.DateTime,
+		Kickoff:         m.Kickoff,
+		DateOnly:        m.DateOnly,
+		Home:            m.Home,
+		HomeLogoURL:     m.HomeLogoURL,
+		Away:            m.Away,
+		AwayLogoURL:     m.AwayLogoURL,
+		IsHome:          sameTeam(clubID, clubName, m.HomeID, m.Home),
+		Score:           m.Score,
+		Status:          m.Status,
+		Note:            m.Note,
+		Venue:           m.Venue,
+		MatchID:         m.MatchID,
+	}
+	// The parsers resolve logos already; fall back for sources that did not
+	if wm.HomeLogoURL == "" {
+		wm.HomeLogoURL = resolveLogo(ctx, m.Home, m.HomeID).URL
+	}
+	if wm.AwayLogoURL == "" {
+		wm.AwayLogoURL = resolveLogo(ctx, m.Away, m.AwayID).URL
+	}
+	return wm
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPickWidgetMatches(t *testing.T) {
+	m := func(id, dateTime string) WidgetMatch { return WidgetMatch{MatchID: id, DateTime: dateTime} }
+	found := [][]WidgetMatch{
+		{m("b", "30.10.2026 19:00"), m("a", "23.10.2026 19:00")},
+		// The same match in a second competition, and one without an ID
+		{m("a", "23.10.2026 19:00"), m("", "06.11.2026 18:00")},
+	}
+	ids := func(matches []WidgetMatch) []string {
+		out := []string{}
+		for _, m := range matches {
+			out = append(out, m.MatchID+"@"+m.DateTime[:5])
+		}
+		return out
+	}
+	tests := []struct {
+		name  string
+		next  bool
+		count int
+		want  []string
+	}{
+		{"next, soonest first", true, 10, []string{"a@23.10", "b@30.10", "@06.11"}},
+		{"last, newest first", false, 10, []string{"@06.11", "b@30.10", "a@23.10"}},
+		{"count", true, 2, []string{"a@23.10", "b@30.10"}},
+	}
+	for _, tt := range tests {
+		if got := ids(pickWidgetMatches(found, tt.next, tt.count)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClubWidgetFromFixtures(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, pragueLocation)
+	tests := []struct {
+		next bool
+		want []string
+	}{
+		{true, []string{"2bbe5f98-d112-40d7-a38a-c2da65d47171", "bc5f73ae-69d3-bd39-5e04-8f5822ec1fd6"}},
+		{false, []string{"124f6b57-8418-67ca-bb34-710890f37aef", "62216d48-93c5-61a7-9f75-cb671989e451"}},
+	}
+	for _, tt := range tests {
+		w, err := clubWidget(context.Background(), "futsal", bizoniID, nil, tt.next, 2, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, m := range w.Matches {
+			got = append(got, m.MatchID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("next=%v: %v, want %v", tt.next, got, tt.want)
+		}
+	}
+}
+
+func TestClubWidgetEndpoints(t *testing.T) {
+	now := time.Now()
+	var next, last ClubWidget
+	getJSON(t, "/club/futsal/"+bizoniID+"/next?count=2", &next)
+	getJSON(t, "/club/futsal/"+bizoniID+"/last?count=2", &last)
+	if len(next.Matches) > 2 || len(last.Matches) > 2 || len(last.Matches) == 0 {
+		t.Fatalf("%d next, %d last matches", len(next.Matches), len(last.Matches))
+	}
+	check := func(name string, matches []WidgetMatch, ok func(WidgetMatch, time.Time) bool, ordered func(a, b time.Time) bool) {
+		var prev time.Time
+		for i, m := range matches {
+			kickoff, _, _ := parseKickoff(m.DateTime)
+			if !ok(m, kickoff) {
+				t.Errorf("%s: %s %s (%s) does not belong here", name, m.MatchID, m.DateTime, m.Status)
+			}
+			if i > 0 && !ordered(prev, kickoff) {
+				t.Errorf("%s: %s out of order", name, m.DateTime)
+			}
+			prev = kickoff
+		}
+	}
+	check("next", next.Matches, func(m WidgetMatch, k time.Time) bool { return m.Status == StatusScheduled && k.After(now) }, time.Time.Before)
+	check("last", last.Matches, func(m WidgetMatch, k time.Time) bool { return m.Status == StatusPlayed && k.Before(now) }, time.Time.After)
+
+	if code := getStatus("/club/futsal/" + bizoniID + "/next?count=0"); code != http.StatusBadRequest {
+		t.Errorf("count=0 = %d, want 400", code)
+	}
+}